@@ -22,6 +22,7 @@ import (
 	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
 )
 
+// +genclient
 // +kubebuilder:object:root=true
 
 // A Certificate resource should be created to ensure an up to date and signed
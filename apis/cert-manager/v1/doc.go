@@ -17,4 +17,5 @@
 // Package v1 contains API Schema definitions for the cert-manager v1 API group
 // +k8s:deepcopy-gen=package,register
 // +groupName=cert-manager.io
+// +groupGoName=Certmanager
 package v1
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// nolint // preserving original code from v1.10.1 jetstack as much as possible
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GenericIssuer is implemented by both Issuer and ClusterIssuer
+// +kubebuilder:object:generate=false
+type GenericIssuer interface {
+	client.Object
+
+	GetObjectMeta() *metav1.ObjectMeta
+	GetSpec() *IssuerSpec
+	GetStatus() *IssuerStatus
+}
+
+var _ GenericIssuer = &Issuer{}
+var _ GenericIssuer = &ClusterIssuer{}
+
+func (c *ClusterIssuer) GetObjectMeta() *metav1.ObjectMeta {
+	return &c.ObjectMeta
+}
+func (c *ClusterIssuer) GetSpec() *IssuerSpec {
+	return &c.Spec
+}
+func (c *ClusterIssuer) GetStatus() *IssuerStatus {
+	return &c.Status
+}
+func (c *ClusterIssuer) SetSpec(spec IssuerSpec) {
+	c.Spec = spec
+}
+func (c *ClusterIssuer) SetStatus(status IssuerStatus) {
+	c.Status = status
+}
+func (c *ClusterIssuer) Copy() GenericIssuer {
+	return c.DeepCopy()
+}
+func (c *Issuer) GetObjectMeta() *metav1.ObjectMeta {
+	return &c.ObjectMeta
+}
+func (c *Issuer) GetSpec() *IssuerSpec {
+	return &c.Spec
+}
+func (c *Issuer) GetStatus() *IssuerStatus {
+	return &c.Status
+}
+func (c *Issuer) SetSpec(spec IssuerSpec) {
+	c.Spec = spec
+}
+func (c *Issuer) SetStatus(status IssuerStatus) {
+	c.Status = status
+}
+func (c *Issuer) Copy() GenericIssuer {
+	return c.DeepCopy()
+}
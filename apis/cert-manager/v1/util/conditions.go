@@ -0,0 +1,168 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package util contains helpers to read and write the status conditions of
+// Certificates, Issuers and ClusterIssuers
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+// Clock is used to set the LastTransitionTime of conditions. It is replaced
+// in tests.
+var Clock clock.PassiveClock = clock.RealClock{}
+
+// IsStale returns true if a condition was set based on an older generation
+// of its object than the current one
+func IsStale(observedGeneration, generation int64) bool {
+	return observedGeneration < generation
+}
+
+// GetCertificateCondition returns the condition of the given type on the
+// Certificate, or nil if it isn't set
+func GetCertificateCondition(crt *certmanagerv1.Certificate, conditionType certmanagerv1.CertificateConditionType) *certmanagerv1.CertificateCondition {
+	for i := range crt.Status.Conditions {
+		if crt.Status.Conditions[i].Type == conditionType {
+			return &crt.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCertificateCondition sets the condition of the given type on the
+// Certificate, observing the Certificate's current generation.
+// LastTransitionTime only changes when the status of the condition does.
+func SetCertificateCondition(crt *certmanagerv1.Certificate, conditionType certmanagerv1.CertificateConditionType,
+	status cmmeta.ConditionStatus, reason, message string) {
+	newCondition := certmanagerv1.CertificateCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: crt.Generation,
+	}
+
+	now := metav1.NewTime(Clock.Now())
+	newCondition.LastTransitionTime = &now
+
+	for i, cond := range crt.Status.Conditions {
+		if cond.Type != conditionType {
+			continue
+		}
+		if cond.Status == status {
+			newCondition.LastTransitionTime = cond.LastTransitionTime
+		}
+		crt.Status.Conditions[i] = newCondition
+		return
+	}
+	crt.Status.Conditions = append(crt.Status.Conditions, newCondition)
+}
+
+// IsCertificateConditionStale returns true if the condition of the given type
+// is missing from the Certificate or was set for an older generation of it
+func IsCertificateConditionStale(crt *certmanagerv1.Certificate, conditionType certmanagerv1.CertificateConditionType) bool {
+	cond := GetCertificateCondition(crt, conditionType)
+	return cond == nil || IsStale(cond.ObservedGeneration, crt.Generation)
+}
+
+// CertificateHasCondition returns true if the Certificate has an up to date
+// condition of the given type with the given status
+func CertificateHasCondition(crt *certmanagerv1.Certificate, conditionType certmanagerv1.CertificateConditionType,
+	status cmmeta.ConditionStatus) bool {
+	cond := GetCertificateCondition(crt, conditionType)
+	return cond != nil && cond.Status == status && !IsStale(cond.ObservedGeneration, crt.Generation)
+}
+
+// IsCertificateReady returns true if the Certificate is Ready for its current
+// generation
+func IsCertificateReady(crt *certmanagerv1.Certificate) bool {
+	return CertificateHasCondition(crt, certmanagerv1.CertificateConditionReady, cmmeta.ConditionTrue)
+}
+
+// IsCertificateIssuing returns true if an issuance has been requested for the
+// current generation of the Certificate
+func IsCertificateIssuing(crt *certmanagerv1.Certificate) bool {
+	return CertificateHasCondition(crt, certmanagerv1.CertificateConditionIssuing, cmmeta.ConditionTrue)
+}
+
+// GetIssuerCondition returns the condition of the given type on the Issuer
+// or ClusterIssuer, or nil if it isn't set
+func GetIssuerCondition(issuer certmanagerv1.GenericIssuer, conditionType certmanagerv1.IssuerConditionType) *certmanagerv1.IssuerCondition {
+	status := issuer.GetStatus()
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetIssuerCondition sets the condition of the given type on the Issuer or
+// ClusterIssuer, observing its current generation.
+// LastTransitionTime only changes when the status of the condition does.
+func SetIssuerCondition(issuer certmanagerv1.GenericIssuer, conditionType certmanagerv1.IssuerConditionType,
+	status cmmeta.ConditionStatus, reason, message string) {
+	newCondition := certmanagerv1.IssuerCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: issuer.GetGeneration(),
+	}
+
+	now := metav1.NewTime(Clock.Now())
+	newCondition.LastTransitionTime = &now
+
+	issuerStatus := issuer.GetStatus()
+	for i, cond := range issuerStatus.Conditions {
+		if cond.Type != conditionType {
+			continue
+		}
+		if cond.Status == status {
+			newCondition.LastTransitionTime = cond.LastTransitionTime
+		}
+		issuerStatus.Conditions[i] = newCondition
+		return
+	}
+	issuerStatus.Conditions = append(issuerStatus.Conditions, newCondition)
+}
+
+// IsIssuerConditionStale returns true if the condition of the given type is
+// missing from the Issuer or ClusterIssuer or was set for an older generation
+// of it
+func IsIssuerConditionStale(issuer certmanagerv1.GenericIssuer, conditionType certmanagerv1.IssuerConditionType) bool {
+	cond := GetIssuerCondition(issuer, conditionType)
+	return cond == nil || IsStale(cond.ObservedGeneration, issuer.GetGeneration())
+}
+
+// IssuerHasCondition returns true if the Issuer or ClusterIssuer has an up to
+// date condition of the given type with the given status
+func IssuerHasCondition(issuer certmanagerv1.GenericIssuer, conditionType certmanagerv1.IssuerConditionType,
+	status cmmeta.ConditionStatus) bool {
+	cond := GetIssuerCondition(issuer, conditionType)
+	return cond != nil && cond.Status == status && !IsStale(cond.ObservedGeneration, issuer.GetGeneration())
+}
+
+// IsIssuerReady returns true if the Issuer or ClusterIssuer is Ready for its
+// current generation
+func IsIssuerReady(issuer certmanagerv1.GenericIssuer) bool {
+	return IssuerHasCondition(issuer, certmanagerv1.IssuerConditionReady, cmmeta.ConditionTrue)
+}
@@ -0,0 +1,228 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+var (
+	earlier = metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	now     = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func certificate(generation int64, conditions ...certmanagerv1.CertificateCondition) *certmanagerv1.Certificate {
+	crt := &certmanagerv1.Certificate{}
+	crt.Generation = generation
+	crt.Status.Conditions = conditions
+	return crt
+}
+
+func TestSetCertificateCondition(t *testing.T) {
+	Clock = fakeclock.NewFakeClock(now)
+	defer func() { Clock = clock.RealClock{} }()
+
+	tests := []struct {
+		name           string
+		crt            *certmanagerv1.Certificate
+		status         cmmeta.ConditionStatus
+		wantTransition time.Time
+		wantConditions int
+	}{
+		{
+			name:           "adds a missing condition",
+			crt:            certificate(1),
+			status:         cmmeta.ConditionTrue,
+			wantTransition: now,
+			wantConditions: 1,
+		},
+		{
+			name: "keeps the transition time when the status is unchanged",
+			crt: certificate(2, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue, LastTransitionTime: &earlier, ObservedGeneration: 1,
+			}),
+			status:         cmmeta.ConditionTrue,
+			wantTransition: earlier.Time,
+			wantConditions: 1,
+		},
+		{
+			name: "updates the transition time when the status changes",
+			crt: certificate(2, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionFalse, LastTransitionTime: &earlier, ObservedGeneration: 1,
+			}),
+			status:         cmmeta.ConditionTrue,
+			wantTransition: now,
+			wantConditions: 1,
+		},
+		{
+			name: "leaves other conditions alone",
+			crt: certificate(1, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, LastTransitionTime: &earlier,
+			}),
+			status:         cmmeta.ConditionFalse,
+			wantTransition: now,
+			wantConditions: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCertificateCondition(tt.crt, certmanagerv1.CertificateConditionReady, tt.status, "Reason", "message")
+
+			if len(tt.crt.Status.Conditions) != tt.wantConditions {
+				t.Fatalf("got %d conditions, want %d", len(tt.crt.Status.Conditions), tt.wantConditions)
+			}
+			cond := GetCertificateCondition(tt.crt, certmanagerv1.CertificateConditionReady)
+			if cond == nil {
+				t.Fatal("Ready condition not set")
+			}
+			if cond.Status != tt.status || cond.Reason != "Reason" || cond.Message != "message" {
+				t.Errorf("unexpected condition %+v", cond)
+			}
+			if cond.ObservedGeneration != tt.crt.Generation {
+				t.Errorf("got observedGeneration %d, want %d", cond.ObservedGeneration, tt.crt.Generation)
+			}
+			if !cond.LastTransitionTime.Time.Equal(tt.wantTransition) {
+				t.Errorf("got lastTransitionTime %v, want %v", cond.LastTransitionTime.Time, tt.wantTransition)
+			}
+		})
+	}
+}
+
+func TestCertificateConditionChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		crt         *certmanagerv1.Certificate
+		wantReady   bool
+		wantIssuing bool
+		wantStale   bool
+	}{
+		{
+			name:      "no conditions",
+			crt:       certificate(1),
+			wantStale: true,
+		},
+		{
+			name: "ready for the current generation",
+			crt: certificate(3, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue, ObservedGeneration: 3,
+			}),
+			wantReady: true,
+		},
+		{
+			name: "ready for an older generation",
+			crt: certificate(3, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue, ObservedGeneration: 2,
+			}),
+			wantStale: true,
+		},
+		{
+			name: "not ready",
+			crt: certificate(1, certmanagerv1.CertificateCondition{
+				Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionFalse, ObservedGeneration: 1,
+			}),
+		},
+		{
+			name: "ready and issuing",
+			crt: certificate(1,
+				certmanagerv1.CertificateCondition{Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue, ObservedGeneration: 1},
+				certmanagerv1.CertificateCondition{Type: certmanagerv1.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, ObservedGeneration: 1},
+			),
+			wantReady:   true,
+			wantIssuing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCertificateReady(tt.crt); got != tt.wantReady {
+				t.Errorf("IsCertificateReady() = %v, want %v", got, tt.wantReady)
+			}
+			if got := IsCertificateIssuing(tt.crt); got != tt.wantIssuing {
+				t.Errorf("IsCertificateIssuing() = %v, want %v", got, tt.wantIssuing)
+			}
+			if got := IsCertificateConditionStale(tt.crt, certmanagerv1.CertificateConditionReady); got != tt.wantStale {
+				t.Errorf("IsCertificateConditionStale() = %v, want %v", got, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestIssuerConditions(t *testing.T) {
+	Clock = fakeclock.NewFakeClock(now)
+	defer func() { Clock = clock.RealClock{} }()
+
+	ready := func(status cmmeta.ConditionStatus, observed int64) []certmanagerv1.IssuerCondition {
+		return []certmanagerv1.IssuerCondition{{
+			Type: certmanagerv1.IssuerConditionReady, Status: status, LastTransitionTime: &earlier, ObservedGeneration: observed,
+		}}
+	}
+	issuer := func(generation int64, conditions []certmanagerv1.IssuerCondition) certmanagerv1.GenericIssuer {
+		iss := &certmanagerv1.Issuer{}
+		iss.Generation = generation
+		iss.Status.Conditions = conditions
+		return iss
+	}
+	clusterIssuer := func(generation int64, conditions []certmanagerv1.IssuerCondition) certmanagerv1.GenericIssuer {
+		iss := &certmanagerv1.ClusterIssuer{}
+		iss.Generation = generation
+		iss.Status.Conditions = conditions
+		return iss
+	}
+
+	tests := []struct {
+		name           string
+		issuer         certmanagerv1.GenericIssuer
+		wantReady      bool
+		wantStale      bool
+		wantTransition time.Time
+	}{
+		{name: "issuer without conditions", issuer: issuer(1, nil), wantStale: true, wantTransition: now},
+		{name: "ready issuer", issuer: issuer(2, ready(cmmeta.ConditionTrue, 2)), wantReady: true, wantTransition: earlier.Time},
+		{name: "issuer ready for an older generation", issuer: issuer(2, ready(cmmeta.ConditionTrue, 1)), wantStale: true, wantTransition: earlier.Time},
+		{name: "not ready cluster issuer", issuer: clusterIssuer(1, ready(cmmeta.ConditionFalse, 1)), wantTransition: now},
+		{name: "ready cluster issuer", issuer: clusterIssuer(1, ready(cmmeta.ConditionTrue, 1)), wantReady: true, wantTransition: earlier.Time},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsIssuerReady(tt.issuer); got != tt.wantReady {
+				t.Errorf("IsIssuerReady() = %v, want %v", got, tt.wantReady)
+			}
+			if got := IsIssuerConditionStale(tt.issuer, certmanagerv1.IssuerConditionReady); got != tt.wantStale {
+				t.Errorf("IsIssuerConditionStale() = %v, want %v", got, tt.wantStale)
+			}
+
+			SetIssuerCondition(tt.issuer, certmanagerv1.IssuerConditionReady, cmmeta.ConditionTrue, "Reason", "message")
+			if !IsIssuerReady(tt.issuer) {
+				t.Errorf("issuer not ready after SetIssuerCondition")
+			}
+			cond := GetIssuerCondition(tt.issuer, certmanagerv1.IssuerConditionReady)
+			if !cond.LastTransitionTime.Time.Equal(tt.wantTransition) {
+				t.Errorf("got lastTransitionTime %v, want %v", cond.LastTransitionTime.Time, tt.wantTransition)
+			}
+		})
+	}
+}
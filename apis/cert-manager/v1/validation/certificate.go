@@ -0,0 +1,203 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package validation checks cert-manager resources without talking to a
+// cluster, following the rules of the cert-manager webhook
+package validation
+
+import (
+	"fmt"
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+// maxCommonNameLength is the upper bound of the X.509 commonName attribute
+const maxCommonNameLength = 64
+
+var knownUsages = map[certmanagerv1.KeyUsage]bool{
+	certmanagerv1.UsageSigning:           true,
+	certmanagerv1.UsageDigitalSignature:  true,
+	certmanagerv1.UsageContentCommitment: true,
+	certmanagerv1.UsageKeyEncipherment:   true,
+	certmanagerv1.UsageKeyAgreement:      true,
+	certmanagerv1.UsageDataEncipherment:  true,
+	certmanagerv1.UsageCertSign:          true,
+	certmanagerv1.UsageCRLSign:           true,
+	certmanagerv1.UsageEncipherOnly:      true,
+	certmanagerv1.UsageDecipherOnly:      true,
+	certmanagerv1.UsageAny:               true,
+	certmanagerv1.UsageServerAuth:        true,
+	certmanagerv1.UsageClientAuth:        true,
+	certmanagerv1.UsageCodeSigning:       true,
+	certmanagerv1.UsageEmailProtection:   true,
+	certmanagerv1.UsageSMIME:             true,
+	certmanagerv1.UsageIPsecEndSystem:    true,
+	certmanagerv1.UsageIPsecTunnel:       true,
+	certmanagerv1.UsageIPsecUser:         true,
+	certmanagerv1.UsageTimestamping:      true,
+	certmanagerv1.UsageOCSPSigning:       true,
+	certmanagerv1.UsageMicrosoftSGC:      true,
+	certmanagerv1.UsageNetscapeSGC:       true,
+}
+
+// ValidateCertificate validates the spec of a Certificate
+func ValidateCertificate(crt *certmanagerv1.Certificate) field.ErrorList {
+	return ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
+}
+
+// ValidateCertificateSpec validates a CertificateSpec, reporting every
+// violation with its field path
+func ValidateCertificateSpec(spec *certmanagerv1.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	if spec.SecretName == "" {
+		el = append(el, field.Required(fldPath.Child("secretName"), "must be specified"))
+	}
+	el = append(el, validateIssuerRef(spec.IssuerRef, fldPath.Child("issuerRef"))...)
+
+	if spec.CommonName == "" && len(spec.DNSNames) == 0 && len(spec.URIs) == 0 &&
+		len(spec.IPAddresses) == 0 && len(spec.EmailAddresses) == 0 {
+		el = append(el, field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris, ipAddresses or emailAddresses must be set"))
+	}
+	if len(spec.CommonName) > maxCommonNameLength {
+		el = append(el, field.TooLong(fldPath.Child("commonName"), spec.CommonName, maxCommonNameLength))
+	}
+	for i, ip := range spec.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			el = append(el, field.Invalid(fldPath.Child("ipAddresses").Index(i), ip, "invalid IP address"))
+		}
+	}
+
+	el = append(el, ValidateDuration(spec, fldPath)...)
+
+	if spec.RevisionHistoryLimit != nil && *spec.RevisionHistoryLimit < 1 {
+		el = append(el, field.Invalid(fldPath.Child("revisionHistoryLimit"), *spec.RevisionHistoryLimit, "must not be less than 1"))
+	}
+
+	el = append(el, validateUsages(spec, fldPath.Child("usages"))...)
+	el = append(el, validatePrivateKey(spec.PrivateKey, fldPath.Child("privateKey"))...)
+
+	if spec.Keystores != nil {
+		keystoresPath := fldPath.Child("keystores")
+		if spec.Keystores.JKS != nil {
+			el = append(el, validatePasswordSecretRef(spec.Keystores.JKS.PasswordSecretRef, keystoresPath.Child("jks", "passwordSecretRef"))...)
+		}
+		if spec.Keystores.PKCS12 != nil {
+			el = append(el, validatePasswordSecretRef(spec.Keystores.PKCS12.PasswordSecretRef, keystoresPath.Child("pkcs12", "passwordSecretRef"))...)
+		}
+	}
+
+	return el
+}
+
+// ValidateDuration checks that the duration of the certificate is at least
+// the cert-manager minimum, and that renewBefore is at least its minimum and
+// shorter than the duration
+func ValidateDuration(spec *certmanagerv1.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	duration := certmanagerv1.DefaultCertificateDuration
+	if spec.Duration != nil {
+		duration = spec.Duration.Duration
+	}
+	if duration < certmanagerv1.MinimumCertificateDuration {
+		el = append(el, field.Invalid(fldPath.Child("duration"), duration.String(),
+			fmt.Sprintf("certificate duration must be at least %s", certmanagerv1.MinimumCertificateDuration)))
+	}
+
+	if spec.RenewBefore == nil {
+		return el
+	}
+	renewBefore := spec.RenewBefore.Duration
+	if renewBefore < certmanagerv1.MinimumRenewBefore {
+		el = append(el, field.Invalid(fldPath.Child("renewBefore"), renewBefore.String(),
+			fmt.Sprintf("certificate renewBefore must be at least %s", certmanagerv1.MinimumRenewBefore)))
+	}
+	if renewBefore >= duration {
+		el = append(el, field.Invalid(fldPath.Child("renewBefore"), renewBefore.String(),
+			fmt.Sprintf("certificate renewBefore must be less than the duration %s", duration)))
+	}
+	return el
+}
+
+func validateIssuerRef(issuerRef cmmeta.ObjectReference, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if issuerRef.Name == "" {
+		el = append(el, field.Required(fldPath.Child("name"), "must be specified"))
+	}
+	if issuerRef.Group == "" || issuerRef.Group == certmanagerv1.GroupVersion.Group {
+		switch issuerRef.Kind {
+		case "", certmanagerv1.IssuerKind, certmanagerv1.ClusterIssuerKind:
+		default:
+			el = append(el, field.NotSupported(fldPath.Child("kind"), issuerRef.Kind,
+				[]string{certmanagerv1.IssuerKind, certmanagerv1.ClusterIssuerKind}))
+		}
+	}
+	return el
+}
+
+// validateUsages checks that every usage is known, and that only CA
+// certificates ask to sign other certificates
+func validateUsages(spec *certmanagerv1.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	for i, usage := range spec.Usages {
+		if !knownUsages[usage] {
+			el = append(el, field.Invalid(fldPath.Index(i), usage, "unknown key usage"))
+			continue
+		}
+		if usage == certmanagerv1.UsageCertSign && !spec.IsCA {
+			el = append(el, field.Invalid(fldPath.Index(i), usage, "only allowed when isCA is true"))
+		}
+	}
+	return el
+}
+
+func validatePrivateKey(pk *certmanagerv1.CertificatePrivateKey, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if pk == nil {
+		return el
+	}
+	switch pk.Algorithm {
+	case "", certmanagerv1.RSAKeyAlgorithm:
+		if pk.Size > 0 && (pk.Size < 2048 || pk.Size > 8192) {
+			el = append(el, field.Invalid(fldPath.Child("size"), pk.Size, "must be between 2048 & 8192 for rsa keyAlgorithm"))
+		}
+	case certmanagerv1.ECDSAKeyAlgorithm:
+		if pk.Size > 0 && pk.Size != 256 && pk.Size != 384 && pk.Size != 521 {
+			el = append(el, field.NotSupported(fldPath.Child("size"), pk.Size, []string{"256", "384", "521"}))
+		}
+	case certmanagerv1.Ed25519KeyAlgorithm:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("algorithm"), pk.Algorithm,
+			[]string{string(certmanagerv1.RSAKeyAlgorithm), string(certmanagerv1.ECDSAKeyAlgorithm), string(certmanagerv1.Ed25519KeyAlgorithm)}))
+	}
+	return el
+}
+
+func validatePasswordSecretRef(ref cmmeta.SecretKeySelector, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if ref.Name == "" {
+		el = append(el, field.Required(fldPath.Child("name"), "must be specified"))
+	}
+	if ref.Key == "" {
+		el = append(el, field.Required(fldPath.Child("key"), "must be specified"))
+	}
+	return el
+}
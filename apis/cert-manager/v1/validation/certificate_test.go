@@ -0,0 +1,180 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package validation
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+func validSpec() certmanagerv1.CertificateSpec {
+	return certmanagerv1.CertificateSpec{
+		CommonName: "example.com",
+		SecretName: "example-tls",
+		IssuerRef:  cmmeta.ObjectReference{Name: "cs-ca-issuer", Kind: certmanagerv1.IssuerKind},
+	}
+}
+
+func duration(d time.Duration) *metav1.Duration {
+	return &metav1.Duration{Duration: d}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestValidateCertificateSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(*certmanagerv1.CertificateSpec)
+		wantFields []string
+	}{
+		{
+			name:   "valid spec",
+			mutate: func(*certmanagerv1.CertificateSpec) {},
+		},
+		{
+			name: "valid with durations, SANs, usages and keystores",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.Duration = duration(24 * time.Hour)
+				s.RenewBefore = duration(time.Hour)
+				s.DNSNames = []string{"example.com"}
+				s.IPAddresses = []string{"10.0.0.1", "::1"}
+				s.IsCA = true
+				s.Usages = []certmanagerv1.KeyUsage{certmanagerv1.UsageCertSign, certmanagerv1.UsageCRLSign}
+				s.RevisionHistoryLimit = int32Ptr(1)
+				s.Keystores = &certmanagerv1.CertificateKeystores{
+					PKCS12: &certmanagerv1.PKCS12Keystore{
+						Create:            true,
+						PasswordSecretRef: cmmeta.SecretKeySelector{LocalObjectReference: cmmeta.LocalObjectReference{Name: "pw"}, Key: "password"},
+					},
+				}
+			},
+		},
+		{
+			name: "missing secret name and issuer",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.SecretName = ""
+				s.IssuerRef = cmmeta.ObjectReference{}
+			},
+			wantFields: []string{"spec.issuerRef.name", "spec.secretName"},
+		},
+		{
+			name:       "unsupported issuer kind",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.IssuerRef.Kind = "Certificate" },
+			wantFields: []string{"spec.issuerRef.kind"},
+		},
+		{
+			name:       "no subject names",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.CommonName = "" },
+			wantFields: []string{"spec"},
+		},
+		{
+			name:       "common name too long",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.CommonName = strings.Repeat("a", 65) },
+			wantFields: []string{"spec.commonName"},
+		},
+		{
+			name:       "invalid IP address",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.IPAddresses = []string{"10.0.0.1", "not-an-ip"} },
+			wantFields: []string{"spec.ipAddresses[1]"},
+		},
+		{
+			name:       "duration too short",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.Duration = duration(30 * time.Minute) },
+			wantFields: []string{"spec.duration"},
+		},
+		{
+			name:       "renewBefore too short",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.RenewBefore = duration(time.Minute) },
+			wantFields: []string{"spec.renewBefore"},
+		},
+		{
+			name: "renewBefore not below duration",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.Duration = duration(2 * time.Hour)
+				s.RenewBefore = duration(2 * time.Hour)
+			},
+			wantFields: []string{"spec.renewBefore"},
+		},
+		{
+			name:       "renewBefore above the default duration",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.RenewBefore = duration(100 * 24 * time.Hour) },
+			wantFields: []string{"spec.renewBefore"},
+		},
+		{
+			name:       "revision history limit below 1",
+			mutate:     func(s *certmanagerv1.CertificateSpec) { s.RevisionHistoryLimit = int32Ptr(0) },
+			wantFields: []string{"spec.revisionHistoryLimit"},
+		},
+		{
+			name: "unknown usage and cert sign without isCA",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.Usages = []certmanagerv1.KeyUsage{certmanagerv1.UsageServerAuth, "flying", certmanagerv1.UsageCertSign}
+			},
+			wantFields: []string{"spec.usages[1]", "spec.usages[2]"},
+		},
+		{
+			name: "invalid private key",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.PrivateKey = &certmanagerv1.CertificatePrivateKey{Algorithm: certmanagerv1.ECDSAKeyAlgorithm, Size: 2048}
+			},
+			wantFields: []string{"spec.privateKey.size"},
+		},
+		{
+			name: "keystores without password refs",
+			mutate: func(s *certmanagerv1.CertificateSpec) {
+				s.Keystores = &certmanagerv1.CertificateKeystores{
+					JKS:    &certmanagerv1.JKSKeystore{Create: true},
+					PKCS12: &certmanagerv1.PKCS12Keystore{Create: true, PasswordSecretRef: cmmeta.SecretKeySelector{Key: "password"}},
+				}
+			},
+			wantFields: []string{
+				"spec.keystores.jks.passwordSecretRef.key",
+				"spec.keystores.jks.passwordSecretRef.name",
+				"spec.keystores.pkcs12.passwordSecretRef.name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crt := &certmanagerv1.Certificate{Spec: validSpec()}
+			tt.mutate(&crt.Spec)
+
+			var gotFields []string
+			for _, err := range ValidateCertificate(crt) {
+				gotFields = append(gotFields, err.Field)
+			}
+			sort.Strings(gotFields)
+			if len(gotFields) == 0 && len(tt.wantFields) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("got errors on %v, want %v", gotFields, tt.wantFields)
+			}
+		})
+	}
+}
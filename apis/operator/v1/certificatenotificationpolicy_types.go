@@ -0,0 +1,138 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+// CertificateNotificationPolicySpec defines the desired state of CertificateNotificationPolicy
+type CertificateNotificationPolicySpec struct {
+	//Selector selects the Certificates notified about. All the Certificates of the cluster are selected when it is empty.
+	// +optional
+	Selector CertificateSelector `json:"selector,omitempty"`
+
+	//ExpiryThresholds are the numbers of days before the status.notAfter of a Certificate at which it is notified as expiring. Each threshold is notified once per certificate issued.
+	// +kubebuilder:default={30,7,1}
+	// +optional
+	ExpiryThresholds []int `json:"expiryThresholds,omitempty"`
+
+	//NotReadyFor notifies a Certificate that has been Ready=False for longer than the duration. Certificates not ready are not notified when it is not set.
+	// +optional
+	NotReadyFor *metav1.Duration `json:"notReadyFor,omitempty"`
+
+	//Webhook is where the notifications are sent
+	Webhook NotificationWebhook `json:"webhook"`
+}
+
+// CertificateSelector selects Certificates by namespace, label and issuer. A Certificate is selected when it matches all the fields set.
+type CertificateSelector struct {
+	//Namespaces are the namespaces of the Certificates
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	//NamespaceSelector selects the namespaces of the Certificates by label
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	//LabelSelector selects the Certificates by label
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	//IssuerRef selects the Certificates of an issuer. Kind defaults to Issuer and Group to cert-manager.io.
+	// +optional
+	IssuerRef *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
+}
+
+// NotificationWebhook is the URL the notifications are POSTed to, as JSON
+type NotificationWebhook struct {
+	//URL is the http or https endpoint receiving the notifications
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	//HMACSecretRef is a key of a Secret holding the key the payloads are signed with, using HMAC-SHA256. The signature is sent in the X-Certificate-Notification-Signature header.
+	// +optional
+	HMACSecretRef *SourceObjectKeySelector `json:"hmacSecretRef,omitempty"`
+}
+
+// NotificationRecord is the delivery state of a notification, kept to send it once and to retry it on failure
+type NotificationRecord struct {
+	//Key identifies the notification, from the certificate, the event and what it was raised for
+	Key string `json:"key"`
+	//Event is Expiring or NotReady
+	Event string `json:"event"`
+	//Certificate is the namespace/name of the Certificate notified about
+	Certificate string `json:"certificate"`
+	//DeliveredTime is when the webhook accepted the notification
+	// +optional
+	DeliveredTime *metav1.Time `json:"deliveredTime,omitempty"`
+	//Attempts is the number of failed deliveries
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	//NextAttemptTime is when the delivery is retried
+	// +optional
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+	//LastError is why the last delivery failed
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// CertificateNotificationPolicyStatus defines the observed state of CertificateNotificationPolicy
+type CertificateNotificationPolicyStatus struct {
+	//Notifications are the notifications due for the selected Certificates, delivered or pending. At most 200 are kept, the others are sent once the first ones are no longer due.
+	// +optional
+	Notifications []NotificationRecord `json:"notifications,omitempty"`
+	//Pending is the number of notifications not delivered yet, including the ones not kept in Notifications
+	// +optional
+	Pending int `json:"pending,omitempty"`
+	//Conditions holds the Delivered condition of the policy
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=certificatenotificationpolicies,scope=Cluster
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.webhook.url"
+//+kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.pending"
+//+kubebuilder:printcolumn:name="Delivered",type="string",JSONPath=".status.conditions[?(@.type==\"Delivered\")].status"
+
+// CertificateNotificationPolicy sends a webhook notification when a selected Certificate is about to expire or fails to be issued
+type CertificateNotificationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateNotificationPolicySpec   `json:"spec,omitempty"`
+	Status CertificateNotificationPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CertificateNotificationPolicyList contains a list of CertificateNotificationPolicy
+type CertificateNotificationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateNotificationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateNotificationPolicy{}, &CertificateNotificationPolicyList{})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+// CertificateRevocationSpec defines the desired state of CertificateRevocation
+type CertificateRevocationSpec struct {
+	//IssuerRef is the CA Issuer or ClusterIssuer that issued the revoked certificate. An Issuer is looked up in the namespace of the CertificateRevocation. The revocations of a ClusterIssuer are only honoured from the namespace of the operator and the namespaces it is configured with.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+
+	//SerialNumber is the serial number of the revoked certificate, in hexadecimal
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	//CertificateName is a Certificate in the namespace of the CertificateRevocation. Its certificate at the time of the revocation is revoked. Ignored when SerialNumber is set.
+	// +optional
+	CertificateName string `json:"certificateName,omitempty"`
+
+	//Reason is the reason code of the revocation in the CRL
+	// +kubebuilder:validation:Enum=unspecified;keyCompromise;cACompromise;affiliationChanged;superseded;cessationOfOperation;certificateHold;privilegeWithdrawn
+	// +kubebuilder:default=unspecified
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// CertificateRevocationStatus defines the observed state of CertificateRevocation
+type CertificateRevocationStatus struct {
+	//SerialNumber is the serial number of the revoked certificate, in hexadecimal
+	// +optional
+	SerialNumber string `json:"serialNumber,omitempty"`
+	//RevocationTime is when the certificate was first published as revoked
+	// +optional
+	RevocationTime *metav1.Time `json:"revocationTime,omitempty"`
+	//Conditions holds the Published condition of the revocation
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Issuer",type="string",JSONPath=".spec.issuerRef.name"
+//+kubebuilder:printcolumn:name="Serial",type="string",JSONPath=".status.serialNumber"
+//+kubebuilder:printcolumn:name="Published",type="string",JSONPath=".status.conditions[?(@.type==\"Published\")].status"
+
+// CertificateRevocation adds a certificate issued by a CA issuer to the CRL the operator publishes for the issuer
+type CertificateRevocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateRevocationSpec   `json:"spec,omitempty"`
+	Status CertificateRevocationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CertificateRevocationList contains a list of CertificateRevocation
+type CertificateRevocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateRevocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateRevocation{}, &CertificateRevocationList{})
+}
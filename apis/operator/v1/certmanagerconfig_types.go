@@ -19,6 +19,8 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
 )
 
 //+kubebuilder:validation:XPreserveUnknownFields
@@ -46,6 +48,30 @@ type CertManagerConfigSpec struct {
 	//RefreshCertsBasedOnCA is a list of CA certificate names. Leaf certificates created from the CA will be refreshed when the CA is refreshed.
 	RefreshCertsBasedOnCA []CACertificate `json:"refreshCertsBasedOnCA,omitempty"`
 
+	//RefreshPolicy limits how fast leaf certificates are re-issued when their CA is refreshed
+	// +optional
+	RefreshPolicy *CertRefreshPolicy `json:"refreshPolicy,omitempty"`
+
+	//RefreshPreview publishes what the next refresh of each CA in RefreshCertsBasedOnCA would re-issue, without re-issuing anything
+	// +optional
+	RefreshPreview *CertRefreshPreview `json:"refreshPreview,omitempty"`
+
+	//CATrustBundle publishes a trust bundle for every CA in RefreshCertsBasedOnCA that keeps trusting the previous CA for a while after a rotation
+	// +optional
+	CATrustBundle *CATrustBundleSpec `json:"caTrustBundle,omitempty"`
+
+	//CSCA configures the Common Services CA chain bootstrapped once cert-manager is ready: the self-signed cs-ss-issuer, the cs-ca-certificate CA and the cs-ca-issuer CA Issuer
+	// +optional
+	CSCA *CSCASpec `json:"csCA,omitempty"`
+
+	//Revocation configures the revocation services the operator runs for the CA issuers
+	// +optional
+	Revocation *RevocationSpec `json:"revocation,omitempty"`
+
+	//Monitoring exposes the metrics of cert-manager-controller and installs the Prometheus monitors and alerts for them
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
 	// +optional
 	License LicenseAcceptance `json:"license,omitempty"`
 }
@@ -70,6 +96,150 @@ type LicenseAcceptance struct {
 //CertManagerContainerSpec defines the spec related to individual operand containers
 type CertManagerContainerSpec struct {
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	//LivenessProbe overrides the timings of the liveness probe of the container
+	// +optional
+	LivenessProbe *ProbeTimings `json:"livenessProbe,omitempty"`
+	//ReadinessProbe overrides the timings of the readiness probe of the container
+	// +optional
+	ReadinessProbe *ProbeTimings `json:"readinessProbe,omitempty"`
+}
+
+//ProbeTimings are the timings of a probe of an operand container. The fields not set keep their default.
+type ProbeTimings struct {
+	//InitialDelaySeconds is the number of seconds after the container has started before the probe is run
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	//TimeoutSeconds is the number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	//PeriodSeconds is how often, in seconds, the probe is run
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	//FailureThreshold is the number of consecutive failures for the probe to be considered failed
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+//CertRefreshPolicy spreads the re-issuance of the leaf certificates of a refreshed CA over time
+type CertRefreshPolicy struct {
+	//MaxConcurrent is the number of leaf certificates being re-issued at the same time. Defaults to 10.
+	// +optional
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	//Interval is the minimum time between starting the re-issuance of two leaf certificates. Defaults to 2s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	//Timeout is how long the re-issuance of a leaf certificate is waited for before it is reported as failed and the refresh moves on. Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+//CertRefreshPreview configures the preview of the CA-based refresh
+type CertRefreshPreview struct {
+	//Enabled turns the preview on. It works whether EnableCertRefresh is set or not.
+	Enabled bool `json:"enabled"`
+	//ConfigMapName is the name of a ConfigMap in the operator namespace the preview is also published to
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+//CATrustBundleSpec configures the trust bundles of the CAs in RefreshCertsBasedOnCA
+type CATrustBundleSpec struct {
+	//Enabled turns on publishing a <certName>-trust-bundle ConfigMap next to each CA certificate. Leaf certificates are only refreshed once the bundle trusts the new CA.
+	Enabled bool `json:"enabled"`
+	//OverlapDuration is how long the previous CA stays in the bundle after a rotation. Defaults to 24h.
+	// +optional
+	OverlapDuration *metav1.Duration `json:"overlapDuration,omitempty"`
+}
+
+//CSCASpec configures the Common Services CA certificate
+type CSCASpec struct {
+	//Disabled stops the operator from creating and reconciling the CS CA chain. The issuers, certificate and secret of the chain that the operator did not create are left alone in any case.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	//KeyAlgorithm is the private key algorithm of the CA. Defaults to RSA.
+	// +kubebuilder:validation:Enum=RSA;ECDSA;Ed25519
+	// +optional
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+	//KeySize is the private key size of the CA. Defaults to 2048 for RSA and 256 for ECDSA.
+	// +optional
+	KeySize int `json:"keySize,omitempty"`
+	//Duration is the lifetime of the CA certificate. Defaults to 17520h.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	//RenewBefore is how long before its expiry the CA certificate is renewed. Defaults to 720h.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+	//Import replaces the self-signed CA with an externally issued CA keypair. The self-signed cs-ca-certificate is no longer managed while it is set. The import is rejected when the CS CA chain was not created by the operator.
+	// +optional
+	Import *CSCAImport `json:"import,omitempty"`
+}
+
+//RevocationSpec configures the revocation services for the certificates of the CA issuers, backed by the CertificateRevocations. They are served by the leader replica of the operator, the other replicas report unready while they are enabled.
+type RevocationSpec struct {
+	//CRL publishes a signed CRL for every CA Issuer and ClusterIssuer, served over HTTP by the operator
+	// +optional
+	CRL *CRLSpec `json:"crl,omitempty"`
+	//OCSP runs an OCSP responder for the certificates of a CA issuer, served over HTTP by the operator
+	// +optional
+	OCSP *OCSPSpec `json:"ocsp,omitempty"`
+}
+
+//CRLSpec configures the CRLs of the CA issuers
+type CRLSpec struct {
+	//Enabled turns on publishing the CRLs. The CRL of cs-ca-issuer is added to the CRL distribution points of the certificates it issues. The CS CA always carries the crl sign usage, so turning the CRLs on or off does not re-issue it.
+	Enabled bool `json:"enabled"`
+	//Validity is the time between the thisUpdate and the nextUpdate of a CRL. Defaults to 24h.
+	// +optional
+	Validity *metav1.Duration `json:"validity,omitempty"`
+	//RefreshBefore is how long before its nextUpdate a CRL is published again. Defaults to 8h. It must be shorter than Validity, a third of Validity is used otherwise.
+	// +optional
+	RefreshBefore *metav1.Duration `json:"refreshBefore,omitempty"`
+}
+
+//OCSPSpec configures the OCSP responder
+type OCSPSpec struct {
+	//Enabled turns on the OCSP responder. When it answers for cs-ca-issuer, its URL is added to the certificates cs-ca-issuer issues.
+	Enabled bool `json:"enabled"`
+	//IssuerRef is the CA Issuer or ClusterIssuer the responder answers for. An Issuer is looked up in the namespace of the operator. Defaults to cs-ca-issuer.
+	// +optional
+	IssuerRef *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
+	//RevokedSerialsConfigMap is a ConfigMap in the namespace of the operator listing revoked serial numbers in hexadecimal, one per line under the key "serials". They are revoked in addition to the CertificateRevocations of the issuer.
+	// +optional
+	RevokedSerialsConfigMap string `json:"revokedSerialsConfigMap,omitempty"`
+	//ResponseValidity is the time between the thisUpdate and the nextUpdate of the responses. Defaults to 1h.
+	// +optional
+	ResponseValidity *metav1.Duration `json:"responseValidity,omitempty"`
+}
+
+//MonitoringSpec configures the monitoring of the cert-manager operands
+type MonitoringSpec struct {
+	//Enabled adds the metrics port to cert-manager-controller and creates its metrics Service. The ServiceMonitor and the PrometheusRule are created when the monitoring.coreos.com API is installed.
+	Enabled bool `json:"enabled"`
+	//Interval is how often Prometheus scrapes the metrics of cert-manager-controller. Defaults to 60s.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	//Labels are added to the ServiceMonitor and the PrometheusRule, to match the selectors of the Prometheus instance
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	//CertificateExpiryThreshold is how long before its expiry a certificate raises an alert. Defaults to 21 days.
+	// +optional
+	CertificateExpiryThreshold *metav1.Duration `json:"certificateExpiryThreshold,omitempty"`
+}
+
+//CSCAImport is the secret holding an externally issued CA keypair to use as the CS CA
+type CSCAImport struct {
+	//SecretName is the secret holding the CA keypair: tls.key, and tls.crt with the CA certificate followed by its intermediates. ca.crt holds the root CA when tls.crt does not end with it.
+	SecretName string `json:"secretName"`
+	//Namespace is the namespace of the secret. Defaults to the namespace of the operator.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	//MinValidity is how long the CA certificate must still be valid for to be imported. Defaults to 720h.
+	// +optional
+	MinValidity *metav1.Duration `json:"minValidity,omitempty"`
 }
 
 type CACertificate struct {
@@ -84,8 +254,110 @@ type CertManagerConfigStatus struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="CertManagerConfig Status"
 	OverallStatus string `json:"certManagerConfigStatus"`
+
+	//CARefresh records, for every CA certificate watched for leaf refresh, the version of the CA last seen and the leaf certificates refreshed after it changed
+	// +optional
+	CARefresh []CARefreshStatus `json:"caRefresh,omitempty"`
+
+	//RefreshPreview lists, for every CA in RefreshCertsBasedOnCA, what its next refresh would re-issue
+	// +optional
+	RefreshPreview []CARefreshPreview `json:"refreshPreview,omitempty"`
+
+	//CSCA reports the state of the Common Services CA chain
+	// +optional
+	CSCA *CSCAStatus `json:"csCA,omitempty"`
+
+	//Conditions reports the state of the integrations of the operator, e.g. RhacmIntegration
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//CSCAStatus is the state of the Common Services CA chain
+type CSCAStatus struct {
+	//Ready is true once the CA certificate and both of its issuers are ready
+	Ready bool `json:"ready"`
+	//Message tells what the chain is waiting for when it is not ready, or why the operator leaves it alone when it was created by another component
+	// +optional
+	Message string `json:"message,omitempty"`
+	//NotAfter is the expiry of the current CA certificate
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	//RenewalTime is when the CA certificate is next renewed
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+	//Imported is true when the CA was imported from the secret in spec.csCA.import
+	// +optional
+	Imported bool `json:"imported,omitempty"`
+}
+
+//CARefreshPreview is what the next refresh of a CA certificate would re-issue
+type CARefreshPreview struct {
+	CertName  string `json:"certName"`
+	Namespace string `json:"namespace"`
+	//Issuers are the Issuers, as Issuer/namespace/name, and ClusterIssuers, as ClusterIssuer/name, signing with the CA or with a CA below it in its chain
+	// +optional
+	Issuers []string `json:"issuers,omitempty"`
+	//Certificates are the certificates, as namespace/name, that would be re-issued
+	// +optional
+	Certificates []string `json:"certificates,omitempty"`
+	//Secrets are the secrets, as namespace/name, whose content would change
+	// +optional
+	Secrets []string `json:"secrets,omitempty"`
+	//Error is set when the preview of the CA could not be computed
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+//CARefreshStatus is the refresh state of a CA certificate whose leaf certificates are refreshed when it is renewed
+type CARefreshStatus struct {
+	CertName   string `json:"certName"`
+	Namespace  string `json:"namespace"`
+	SecretName string `json:"secretName"`
+	//Fingerprint is the SHA-256 fingerprint of the CA certificate last seen in the secret
+	Fingerprint string `json:"fingerprint"`
+	//LastRefreshTime is the time the leaf certificates were last refreshed because the CA changed
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+	//RefreshedCertificates lists the leaf certificates, as namespace/name, re-issued after the last CA change
+	// +optional
+	RefreshedCertificates []string `json:"refreshedCertificates,omitempty"`
+	//ChainLevel is the level of the CA chain being refreshed, counted from the CA, while a chain refresh is in progress
+	// +optional
+	ChainLevel int `json:"chainLevel,omitempty"`
+	//PendingCertificates are the certificates being re-issued that are not Ready with a new revision yet
+	// +optional
+	PendingCertificates []PendingCertificate `json:"pendingCertificates,omitempty"`
+	//QueuedCertificates are the certificates waiting for their re-issuance to start, in order
+	// +optional
+	QueuedCertificates []PendingCertificate `json:"queuedCertificates,omitempty"`
+	//LevelCAs lists the CA certificates, as namespace/name, re-issued in the current chain level
+	// +optional
+	LevelCAs []string `json:"levelCAs,omitempty"`
+	//FailedCertificates lists the certificates, as namespace/name, that were not re-issued within the refresh timeout
+	// +optional
+	FailedCertificates []string `json:"failedCertificates,omitempty"`
+	//NextRefreshTime is the earliest time the re-issuance of the next queued certificate may start
+	// +optional
+	NextRefreshTime *metav1.Time `json:"nextRefreshTime,omitempty"`
+	//TrustOverlapUntil is the time the previous CA is dropped from the trust bundle of the CA
+	// +optional
+	TrustOverlapUntil *metav1.Time `json:"trustOverlapUntil,omitempty"`
+}
+
+//PendingCertificate is a certificate whose re-issuance was requested and is waited for
+type PendingCertificate struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	//Revision is the revision of the certificate when its re-issuance was requested
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	//StartTime is the time the re-issuance was requested
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
 }
 
+//+genclient
+//+genclient:nonNamespaced
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=certmanagerconfigs,scope=Cluster
@@ -0,0 +1,135 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrustBundleSpec defines the desired state of TrustBundle
+type TrustBundleSpec struct {
+	//Sources are the CA certificates assembled into the bundle, in order. Duplicates are only added once.
+	// +kubebuilder:validation:MinItems=1
+	Sources []TrustBundleSource `json:"sources"`
+
+	//Target is where the bundle is written to
+	Target TrustBundleTarget `json:"target"`
+}
+
+// TrustBundleSource is one source of CA certificates. Exactly one of its fields is set.
+type TrustBundleSource struct {
+	//Secret is a key of a Secret holding PEM encoded CA certificates, e.g. the tls.crt or ca.crt of cs-ca-certificate-secret
+	// +optional
+	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+
+	//ConfigMap is a key of a ConfigMap holding PEM encoded CA certificates
+	// +optional
+	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
+
+	//InLine is a PEM encoded list of CA certificates
+	// +optional
+	InLine string `json:"inLine,omitempty"`
+}
+
+// SourceObjectKeySelector references a key of a Secret or ConfigMap
+type SourceObjectKeySelector struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// TrustBundleTarget is the ConfigMap the bundle is written to, in every selected namespace
+type TrustBundleTarget struct {
+	//ConfigMap is the key of the ConfigMap holding the PEM bundle. The ConfigMap has the name of the TrustBundle.
+	ConfigMap TargetConfigMap `json:"configMap"`
+
+	//NamespaceSelector selects the namespaces the bundle is written to. All namespaces are selected when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	//AdditionalFormats are truststores written next to the PEM bundle, in the binaryData of the ConfigMap
+	// +optional
+	AdditionalFormats *TrustBundleFormats `json:"additionalFormats,omitempty"`
+}
+
+// TargetConfigMap is the key of the target ConfigMap holding the PEM bundle
+type TargetConfigMap struct {
+	Key string `json:"key"`
+}
+
+// TrustBundleFormats are the truststore formats written next to the PEM bundle
+type TrustBundleFormats struct {
+	//JKS writes the bundle as a Java keystore
+	// +optional
+	JKS *TrustStoreKey `json:"jks,omitempty"`
+	//PKCS12 writes the bundle as a PKCS#12 truststore
+	// +optional
+	PKCS12 *TrustStoreKey `json:"pkcs12,omitempty"`
+}
+
+// TrustStoreKey is the key and password of a truststore in the target ConfigMap
+type TrustStoreKey struct {
+	Key string `json:"key"`
+	//Password protects the truststore. Defaults to "changeit".
+	// +kubebuilder:default=changeit
+	// +optional
+	Password string `json:"password,omitempty"`
+}
+
+// TrustBundleStatus defines the observed state of TrustBundle
+type TrustBundleStatus struct {
+	//Certificates is the number of distinct CA certificates in the bundle
+	// +optional
+	Certificates int `json:"certificates,omitempty"`
+	//Namespaces is the number of namespaces the bundle is written to
+	// +optional
+	Namespaces int `json:"namespaces,omitempty"`
+	//Conditions holds the Synced condition of the bundle
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+genclient
+//+genclient:nonNamespaced
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=trustbundles,scope=Cluster
+//+kubebuilder:printcolumn:name="Certificates",type="integer",JSONPath=".status.certificates"
+//+kubebuilder:printcolumn:name="Namespaces",type="integer",JSONPath=".status.namespaces"
+//+kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+
+// TrustBundle assembles CA certificates from Secrets, ConfigMaps and inline PEM into a ConfigMap written to every selected namespace
+type TrustBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrustBundleSpec   `json:"spec,omitempty"`
+	Status TrustBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TrustBundleList contains a list of TrustBundle
+type TrustBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrustBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrustBundle{}, &TrustBundleList{})
+}
@@ -22,6 +22,8 @@ limitations under the License.
 package v1
 
 import (
+	meta_cert_managerv1 "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -40,13 +42,217 @@ func (in *CACertificate) DeepCopy() *CACertificate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARefreshPreview) DeepCopyInto(out *CARefreshPreview) {
+	*out = *in
+	if in.Issuers != nil {
+		in, out := &in.Issuers, &out.Issuers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARefreshPreview.
+func (in *CARefreshPreview) DeepCopy() *CARefreshPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(CARefreshPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CARefreshStatus) DeepCopyInto(out *CARefreshStatus) {
+	*out = *in
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RefreshedCertificates != nil {
+		in, out := &in.RefreshedCertificates, &out.RefreshedCertificates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingCertificates != nil {
+		in, out := &in.PendingCertificates, &out.PendingCertificates
+		*out = make([]PendingCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueuedCertificates != nil {
+		in, out := &in.QueuedCertificates, &out.QueuedCertificates
+		*out = make([]PendingCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LevelCAs != nil {
+		in, out := &in.LevelCAs, &out.LevelCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedCertificates != nil {
+		in, out := &in.FailedCertificates, &out.FailedCertificates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextRefreshTime != nil {
+		in, out := &in.NextRefreshTime, &out.NextRefreshTime
+		*out = (*in).DeepCopy()
+	}
+	if in.TrustOverlapUntil != nil {
+		in, out := &in.TrustOverlapUntil, &out.TrustOverlapUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CARefreshStatus.
+func (in *CARefreshStatus) DeepCopy() *CARefreshStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CARefreshStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CATrustBundleSpec) DeepCopyInto(out *CATrustBundleSpec) {
+	*out = *in
+	if in.OverlapDuration != nil {
+		in, out := &in.OverlapDuration, &out.OverlapDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CATrustBundleSpec.
+func (in *CATrustBundleSpec) DeepCopy() *CATrustBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CATrustBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CRLSpec) DeepCopyInto(out *CRLSpec) {
+	*out = *in
+	if in.Validity != nil {
+		in, out := &in.Validity, &out.Validity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RefreshBefore != nil {
+		in, out := &in.RefreshBefore, &out.RefreshBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRLSpec.
+func (in *CRLSpec) DeepCopy() *CRLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CRLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSCAImport) DeepCopyInto(out *CSCAImport) {
+	*out = *in
+	if in.MinValidity != nil {
+		in, out := &in.MinValidity, &out.MinValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSCAImport.
+func (in *CSCAImport) DeepCopy() *CSCAImport {
+	if in == nil {
+		return nil
+	}
+	out := new(CSCAImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSCASpec) DeepCopyInto(out *CSCASpec) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Import != nil {
+		in, out := &in.Import, &out.Import
+		*out = new(CSCAImport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSCASpec.
+func (in *CSCASpec) DeepCopy() *CSCASpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CSCASpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSCAStatus) DeepCopyInto(out *CSCAStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSCAStatus.
+func (in *CSCAStatus) DeepCopy() *CSCAStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CSCAStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertManagerConfig) DeepCopyInto(out *CertManagerConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerConfig.
@@ -121,6 +327,36 @@ func (in *CertManagerConfigSpec) DeepCopyInto(out *CertManagerConfigSpec) {
 		*out = make([]CACertificate, len(*in))
 		copy(*out, *in)
 	}
+	if in.RefreshPolicy != nil {
+		in, out := &in.RefreshPolicy, &out.RefreshPolicy
+		*out = new(CertRefreshPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshPreview != nil {
+		in, out := &in.RefreshPreview, &out.RefreshPreview
+		*out = new(CertRefreshPreview)
+		**out = **in
+	}
+	if in.CATrustBundle != nil {
+		in, out := &in.CATrustBundle, &out.CATrustBundle
+		*out = new(CATrustBundleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CSCA != nil {
+		in, out := &in.CSCA, &out.CSCA
+		*out = new(CSCASpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Revocation != nil {
+		in, out := &in.Revocation, &out.Revocation
+		*out = new(RevocationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.License = in.License
 }
 
@@ -137,6 +373,32 @@ func (in *CertManagerConfigSpec) DeepCopy() *CertManagerConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertManagerConfigStatus) DeepCopyInto(out *CertManagerConfigStatus) {
 	*out = *in
+	if in.CARefresh != nil {
+		in, out := &in.CARefresh, &out.CARefresh
+		*out = make([]CARefreshStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RefreshPreview != nil {
+		in, out := &in.RefreshPreview, &out.RefreshPreview
+		*out = make([]CARefreshPreview, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CSCA != nil {
+		in, out := &in.CSCA, &out.CSCA
+		*out = new(CSCAStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerConfigStatus.
@@ -153,6 +415,16 @@ func (in *CertManagerConfigStatus) DeepCopy() *CertManagerConfigStatus {
 func (in *CertManagerContainerSpec) DeepCopyInto(out *CertManagerContainerSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ProbeTimings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerContainerSpec.
@@ -166,16 +438,706 @@ func (in *CertManagerContainerSpec) DeepCopy() *CertManagerContainerSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LicenseAcceptance) DeepCopyInto(out *LicenseAcceptance) {
+func (in *CertRefreshPolicy) DeepCopyInto(out *CertRefreshPolicy) {
 	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseAcceptance.
-func (in *LicenseAcceptance) DeepCopy() *LicenseAcceptance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertRefreshPolicy.
+func (in *CertRefreshPolicy) DeepCopy() *CertRefreshPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(LicenseAcceptance)
+	out := new(CertRefreshPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertRefreshPreview) DeepCopyInto(out *CertRefreshPreview) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertRefreshPreview.
+func (in *CertRefreshPreview) DeepCopy() *CertRefreshPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(CertRefreshPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateNotificationPolicy) DeepCopyInto(out *CertificateNotificationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateNotificationPolicy.
+func (in *CertificateNotificationPolicy) DeepCopy() *CertificateNotificationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateNotificationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateNotificationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateNotificationPolicyList) DeepCopyInto(out *CertificateNotificationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateNotificationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateNotificationPolicyList.
+func (in *CertificateNotificationPolicyList) DeepCopy() *CertificateNotificationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateNotificationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateNotificationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateNotificationPolicySpec) DeepCopyInto(out *CertificateNotificationPolicySpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.ExpiryThresholds != nil {
+		in, out := &in.ExpiryThresholds, &out.ExpiryThresholds
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotReadyFor != nil {
+		in, out := &in.NotReadyFor, &out.NotReadyFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.Webhook.DeepCopyInto(&out.Webhook)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateNotificationPolicySpec.
+func (in *CertificateNotificationPolicySpec) DeepCopy() *CertificateNotificationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateNotificationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateNotificationPolicyStatus) DeepCopyInto(out *CertificateNotificationPolicyStatus) {
+	*out = *in
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateNotificationPolicyStatus.
+func (in *CertificateNotificationPolicyStatus) DeepCopy() *CertificateNotificationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateNotificationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRevocation) DeepCopyInto(out *CertificateRevocation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRevocation.
+func (in *CertificateRevocation) DeepCopy() *CertificateRevocation {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRevocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateRevocation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRevocationList) DeepCopyInto(out *CertificateRevocationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateRevocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRevocationList.
+func (in *CertificateRevocationList) DeepCopy() *CertificateRevocationList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRevocationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateRevocationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRevocationSpec) DeepCopyInto(out *CertificateRevocationSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRevocationSpec.
+func (in *CertificateRevocationSpec) DeepCopy() *CertificateRevocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRevocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRevocationStatus) DeepCopyInto(out *CertificateRevocationStatus) {
+	*out = *in
+	if in.RevocationTime != nil {
+		in, out := &in.RevocationTime, &out.RevocationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRevocationStatus.
+func (in *CertificateRevocationStatus) DeepCopy() *CertificateRevocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRevocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSelector) DeepCopyInto(out *CertificateSelector) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(meta_cert_managerv1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSelector.
+func (in *CertificateSelector) DeepCopy() *CertificateSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseAcceptance) DeepCopyInto(out *LicenseAcceptance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseAcceptance.
+func (in *LicenseAcceptance) DeepCopy() *LicenseAcceptance {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseAcceptance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CertificateExpiryThreshold != nil {
+		in, out := &in.CertificateExpiryThreshold, &out.CertificateExpiryThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationRecord) DeepCopyInto(out *NotificationRecord) {
+	*out = *in
+	if in.DeliveredTime != nil {
+		in, out := &in.DeliveredTime, &out.DeliveredTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRecord.
+func (in *NotificationRecord) DeepCopy() *NotificationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationWebhook) DeepCopyInto(out *NotificationWebhook) {
+	*out = *in
+	if in.HMACSecretRef != nil {
+		in, out := &in.HMACSecretRef, &out.HMACSecretRef
+		*out = new(SourceObjectKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationWebhook.
+func (in *NotificationWebhook) DeepCopy() *NotificationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCSPSpec) DeepCopyInto(out *OCSPSpec) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(meta_cert_managerv1.ObjectReference)
+		**out = **in
+	}
+	if in.ResponseValidity != nil {
+		in, out := &in.ResponseValidity, &out.ResponseValidity
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCSPSpec.
+func (in *OCSPSpec) DeepCopy() *OCSPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OCSPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingCertificate) DeepCopyInto(out *PendingCertificate) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingCertificate.
+func (in *PendingCertificate) DeepCopy() *PendingCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeTimings) DeepCopyInto(out *ProbeTimings) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeTimings.
+func (in *ProbeTimings) DeepCopy() *ProbeTimings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeTimings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevocationSpec) DeepCopyInto(out *RevocationSpec) {
+	*out = *in
+	if in.CRL != nil {
+		in, out := &in.CRL, &out.CRL
+		*out = new(CRLSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCSP != nil {
+		in, out := &in.OCSP, &out.OCSP
+		*out = new(OCSPSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevocationSpec.
+func (in *RevocationSpec) DeepCopy() *RevocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RevocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceObjectKeySelector) DeepCopyInto(out *SourceObjectKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceObjectKeySelector.
+func (in *SourceObjectKeySelector) DeepCopy() *SourceObjectKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceObjectKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetConfigMap) DeepCopyInto(out *TargetConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetConfigMap.
+func (in *TargetConfigMap) DeepCopy() *TargetConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundle) DeepCopyInto(out *TrustBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundle.
+func (in *TrustBundle) DeepCopy() *TrustBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleFormats) DeepCopyInto(out *TrustBundleFormats) {
+	*out = *in
+	if in.JKS != nil {
+		in, out := &in.JKS, &out.JKS
+		*out = new(TrustStoreKey)
+		**out = **in
+	}
+	if in.PKCS12 != nil {
+		in, out := &in.PKCS12, &out.PKCS12
+		*out = new(TrustStoreKey)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleFormats.
+func (in *TrustBundleFormats) DeepCopy() *TrustBundleFormats {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleFormats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleList) DeepCopyInto(out *TrustBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TrustBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleList.
+func (in *TrustBundleList) DeepCopy() *TrustBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrustBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleSource) DeepCopyInto(out *TrustBundleSource) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SourceObjectKeySelector)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(SourceObjectKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleSource.
+func (in *TrustBundleSource) DeepCopy() *TrustBundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleSpec) DeepCopyInto(out *TrustBundleSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]TrustBundleSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleSpec.
+func (in *TrustBundleSpec) DeepCopy() *TrustBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleStatus) DeepCopyInto(out *TrustBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleStatus.
+func (in *TrustBundleStatus) DeepCopy() *TrustBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustBundleTarget) DeepCopyInto(out *TrustBundleTarget) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalFormats != nil {
+		in, out := &in.AdditionalFormats, &out.AdditionalFormats
+		*out = new(TrustBundleFormats)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustBundleTarget.
+func (in *TrustBundleTarget) DeepCopy() *TrustBundleTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustBundleTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustStoreKey) DeepCopyInto(out *TrustStoreKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustStoreKey.
+func (in *TrustStoreKey) DeepCopy() *TrustStoreKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustStoreKey)
 	in.DeepCopyInto(out)
 	return out
 }
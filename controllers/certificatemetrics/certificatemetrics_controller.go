@@ -0,0 +1,67 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificatemetrics
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+)
+
+var logd = log.Log.WithName("controller_certificatemetrics")
+
+// CertificateMetricsReconciler reports every Certificate of the cluster to
+// the Collector, whatever the version of the cert-manager operand issuing it
+type CertificateMetricsReconciler struct {
+	Client    client.Client
+	Collector *Collector
+}
+
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
+
+// Reconcile records the status of the certificate, or drops its metrics once
+// it is deleted
+func (r *CertificateMetricsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cert := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cert); err != nil {
+		if errors.IsNotFound(err) {
+			logd.V(2).Info("Dropping the metrics of deleted certificate", "Request.Namespace", req.Namespace, "Request.Name", req.Name)
+			r.Collector.delete(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	r.Collector.set(cert)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificateMetricsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("certificatemetrics-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &certmanagerv1.Certificate{}}, &handler.EnqueueRequestForObject{})
+}
@@ -0,0 +1,99 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificatemetrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+func TestReconcileExportsCertificateMetrics(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	notAfter := metav1.NewTime(now.Add(30 * 24 * time.Hour))
+	renewalTime := metav1.NewTime(now.Add(20 * 24 * time.Hour))
+	lastFailure := metav1.NewTime(now.Add(-90 * time.Second))
+	cert := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "app"},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "web-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: "cs-ca-issuer"},
+		},
+		Status: certmanagerv1.CertificateStatus{
+			NotAfter:        &notAfter,
+			RenewalTime:     &renewalTime,
+			LastFailureTime: &lastFailure,
+			Conditions: []certmanagerv1.CertificateCondition{
+				{Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionFalse},
+			},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := certmanagerv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cert).Build()
+	collector := NewCollector()
+	collector.now = func() time.Time { return now }
+	r := &CertificateMetricsReconciler{Client: c, Collector: collector}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "web"}}
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+# HELP ibm_cert_manager_certificate_expiration_timestamp_seconds The date after which the certificate expires, from status.notAfter.
+# TYPE ibm_cert_manager_certificate_expiration_timestamp_seconds gauge
+ibm_cert_manager_certificate_expiration_timestamp_seconds{issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 1.702592e+09
+# HELP ibm_cert_manager_certificate_ready_status The status of the Ready condition of the certificate.
+# TYPE ibm_cert_manager_certificate_ready_status gauge
+ibm_cert_manager_certificate_ready_status{condition="False",issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 1
+ibm_cert_manager_certificate_ready_status{condition="True",issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 0
+ibm_cert_manager_certificate_ready_status{condition="Unknown",issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 0
+# HELP ibm_cert_manager_certificate_renewal_timestamp_seconds The date the certificate is due to be renewed, from status.renewalTime.
+# TYPE ibm_cert_manager_certificate_renewal_timestamp_seconds gauge
+ibm_cert_manager_certificate_renewal_timestamp_seconds{issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 1.701728e+09
+# HELP ibm_cert_manager_certificate_seconds_since_last_failure The time since the last failed issuance of the certificate, from status.lastFailureTime.
+# TYPE ibm_cert_manager_certificate_seconds_since_last_failure gauge
+ibm_cert_manager_certificate_seconds_since_last_failure{issuer_kind="Issuer",issuer_name="cs-ca-issuer",name="web",namespace="app"} 90
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.Delete(context.TODO(), cert); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("got %d metrics once the certificate is deleted, want none", n)
+	}
+}
@@ -0,0 +1,182 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificatemetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+const metricsNamespace = "ibm_cert_manager"
+
+var (
+	certificateLabels = []string{"namespace", "name", "issuer_name", "issuer_kind"}
+
+	notAfterDesc = prometheus.NewDesc(metricsNamespace+"_certificate_expiration_timestamp_seconds",
+		"The date after which the certificate expires, from status.notAfter.", certificateLabels, nil)
+	renewalTimeDesc = prometheus.NewDesc(metricsNamespace+"_certificate_renewal_timestamp_seconds",
+		"The date the certificate is due to be renewed, from status.renewalTime.", certificateLabels, nil)
+	readyDesc = prometheus.NewDesc(metricsNamespace+"_certificate_ready_status",
+		"The status of the Ready condition of the certificate.", append(certificateLabels, "condition"), nil)
+	lastFailureDesc = prometheus.NewDesc(metricsNamespace+"_certificate_seconds_since_last_failure",
+		"The time since the last failed issuance of the certificate, from status.lastFailureTime.", certificateLabels, nil)
+
+	issuerReadyDesc = prometheus.NewDesc(metricsNamespace+"_issuer_ready_status",
+		"The status of the Ready condition of the Issuer or ClusterIssuer.", []string{"namespace", "name", "kind", "condition"}, nil)
+)
+
+// readyStatuses are the values of the condition label of the Ready metric
+var readyStatuses = []cmmeta.ConditionStatus{cmmeta.ConditionTrue, cmmeta.ConditionFalse, cmmeta.ConditionUnknown}
+
+// certificateState is what the metrics of a certificate are computed from
+type certificateState struct {
+	issuerName  string
+	issuerKind  string
+	notAfter    *metav1.Time
+	renewalTime *metav1.Time
+	ready       cmmeta.ConditionStatus
+	lastFailure *metav1.Time
+}
+
+// issuerKey identifies an Issuer, or a ClusterIssuer by its name only
+type issuerKey struct {
+	kind string
+	types.NamespacedName
+}
+
+// Collector publishes the metrics of the certificates and issuers reported
+// to it by the CertificateMetricsReconciler and IssuerMetricsReconciler. The
+// metrics are computed when scraped, so the time since the last failure keeps
+// growing between two reconciles.
+type Collector struct {
+	mu           sync.RWMutex
+	certificates map[types.NamespacedName]certificateState
+	issuers      map[issuerKey]cmmeta.ConditionStatus
+	now          func() time.Time
+}
+
+// NewCollector returns a Collector without certificates
+func NewCollector() *Collector {
+	return &Collector{
+		certificates: map[types.NamespacedName]certificateState{},
+		issuers:      map[issuerKey]cmmeta.ConditionStatus{},
+		now:          time.Now,
+	}
+}
+
+// set records the state of the certificate
+func (c *Collector) set(cert *certmanagerv1.Certificate) {
+	state := certificateState{
+		issuerName:  cert.Spec.IssuerRef.Name,
+		issuerKind:  cert.Spec.IssuerRef.Kind,
+		notAfter:    cert.Status.NotAfter,
+		renewalTime: cert.Status.RenewalTime,
+		ready:       cmmeta.ConditionUnknown,
+		lastFailure: cert.Status.LastFailureTime,
+	}
+	if state.issuerKind == "" {
+		state.issuerKind = certmanagerv1.IssuerKind
+	}
+	for _, condition := range cert.Status.Conditions {
+		if condition.Type == certmanagerv1.CertificateConditionReady {
+			state.ready = condition.Status
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certificates[types.NamespacedName{Namespace: cert.Namespace, Name: cert.Name}] = state
+}
+
+// delete drops the metrics of a deleted certificate
+func (c *Collector) delete(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.certificates, key)
+}
+
+// setIssuer records the Ready condition of the issuer
+func (c *Collector) setIssuer(kind string, issuer certmanagerv1.GenericIssuer) {
+	ready := cmmeta.ConditionUnknown
+	for _, condition := range issuer.GetStatus().Conditions {
+		if condition.Type == certmanagerv1.IssuerConditionReady {
+			ready = condition.Status
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.issuers[issuerKey{kind: kind, NamespacedName: types.NamespacedName{Namespace: issuer.GetNamespace(), Name: issuer.GetName()}}] = ready
+}
+
+// deleteIssuer drops the metrics of a deleted issuer
+func (c *Collector) deleteIssuer(kind string, key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.issuers, issuerKey{kind: kind, NamespacedName: key})
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- notAfterDesc
+	ch <- renewalTimeDesc
+	ch <- readyDesc
+	ch <- lastFailureDesc
+	ch <- issuerReadyDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	now := c.now()
+	for key, state := range c.certificates {
+		labels := []string{key.Namespace, key.Name, state.issuerName, state.issuerKind}
+		if state.notAfter != nil {
+			ch <- prometheus.MustNewConstMetric(notAfterDesc, prometheus.GaugeValue, float64(state.notAfter.Unix()), labels...)
+		}
+		if state.renewalTime != nil {
+			ch <- prometheus.MustNewConstMetric(renewalTimeDesc, prometheus.GaugeValue, float64(state.renewalTime.Unix()), labels...)
+		}
+		for _, status := range readyStatuses {
+			value := 0.0
+			if state.ready == status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(readyDesc, prometheus.GaugeValue, value, append(labels, string(status))...)
+		}
+		if state.lastFailure != nil {
+			ch <- prometheus.MustNewConstMetric(lastFailureDesc, prometheus.GaugeValue, now.Sub(state.lastFailure.Time).Seconds(), labels...)
+		}
+	}
+	for key, ready := range c.issuers {
+		for _, status := range readyStatuses {
+			value := 0.0
+			if ready == status {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(issuerReadyDesc, prometheus.GaugeValue, value, key.Namespace, key.Name, key.kind, string(status))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificatemetrics
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+)
+
+// IssuerMetricsReconciler reports the Ready condition of every Issuer and
+// ClusterIssuer to the Collector. The requests are the namespace and name of
+// an Issuer, or the name of a ClusterIssuer.
+type IssuerMetricsReconciler struct {
+	Client    client.Client
+	Collector *Collector
+}
+
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;clusterissuers,verbs=get;list;watch
+
+// Reconcile records the Ready condition of the issuer, or drops its metrics
+// once it is deleted
+func (r *IssuerMetricsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var issuer certmanagerv1.GenericIssuer = &certmanagerv1.Issuer{}
+	kind := certmanagerv1.IssuerKind
+	if req.Namespace == "" {
+		issuer = &certmanagerv1.ClusterIssuer{}
+		kind = certmanagerv1.ClusterIssuerKind
+	}
+	if err := r.Client.Get(ctx, req.NamespacedName, issuer); err != nil {
+		if errors.IsNotFound(err) {
+			r.Collector.deleteIssuer(kind, req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	r.Collector.setIssuer(kind, issuer)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IssuerMetricsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("issuermetrics-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Kind{Type: &certmanagerv1.Issuer{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &certmanagerv1.ClusterIssuer{}}, &handler.EnqueueRequestForObject{})
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificatemetrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+)
+
+func TestReconcileExportsIssuerReadiness(t *testing.T) {
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cs-ca-issuer", Namespace: "app"},
+		Status: certmanagerv1.IssuerStatus{Conditions: []certmanagerv1.IssuerCondition{
+			{Type: certmanagerv1.IssuerConditionReady, Status: cmmeta.ConditionTrue},
+		}},
+	}
+	clusterIssuer := &certmanagerv1.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "acme"}}
+
+	scheme := runtime.NewScheme()
+	if err := certmanagerv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(issuer, clusterIssuer).Build()
+	collector := NewCollector()
+	r := &IssuerMetricsReconciler{Client: c, Collector: collector}
+
+	for _, key := range []types.NamespacedName{{Namespace: "app", Name: "cs-ca-issuer"}, {Name: "acme"}} {
+		if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := `
+# HELP ibm_cert_manager_issuer_ready_status The status of the Ready condition of the Issuer or ClusterIssuer.
+# TYPE ibm_cert_manager_issuer_ready_status gauge
+ibm_cert_manager_issuer_ready_status{condition="False",kind="ClusterIssuer",name="acme",namespace=""} 0
+ibm_cert_manager_issuer_ready_status{condition="False",kind="Issuer",name="cs-ca-issuer",namespace="app"} 0
+ibm_cert_manager_issuer_ready_status{condition="True",kind="ClusterIssuer",name="acme",namespace=""} 0
+ibm_cert_manager_issuer_ready_status{condition="True",kind="Issuer",name="cs-ca-issuer",namespace="app"} 1
+ibm_cert_manager_issuer_ready_status{condition="Unknown",kind="ClusterIssuer",name="acme",namespace=""} 1
+ibm_cert_manager_issuer_ready_status{condition="Unknown",kind="Issuer",name="cs-ca-issuer",namespace="app"} 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want), "ibm_cert_manager_issuer_ready_status"); err != nil {
+		t.Error(err)
+	}
+
+	if err := c.Delete(context.TODO(), clusterIssuer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme"}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(collector, "ibm_cert_manager_issuer_ready_status"); n != 3 {
+		t.Errorf("got %d issuer metrics once the ClusterIssuer is deleted, want 3", n)
+	}
+}
@@ -0,0 +1,515 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+var logd = log.Log.WithName("controller_certificaterefresh")
+
+// CARenewedReason is the reason set on the Issuing condition of the leaf
+// certificates re-issued because their CA changed
+const CARenewedReason = "CARenewed"
+
+// refreshCheckInterval is how often the certificates being re-issued are
+// checked while a refresh is in progress
+const refreshCheckInterval = 10 * time.Second
+
+// maxChainDepth bounds the number of levels walked below a CA, guarding
+// against issuer cycles
+const maxChainDepth = 10
+
+// CertificateRefreshReconciler re-issues the leaf certificates of the CAs
+// listed in the CertManagerConfig when the CA certificate in their secret
+// changes. Requests are keyed by the CA secret.
+type CertificateRefreshReconciler struct {
+	Client   client.Client
+	Reader   client.Reader
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certmanagerconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certmanagerconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=clusterissuers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates/status,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile compares the CA certificate in the secret with the one last seen
+// and, when it changed, forces the certificates issued by the Issuers and
+// ClusterIssuers backed by the secret to be re-issued
+func (r *CertificateRefreshReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	config := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !refreshEnabled(config) {
+		reqLogger.V(2).Info("Certificate refresh is disabled")
+		return ctrl.Result{}, nil
+	}
+
+	ca, err := r.findCACertificate(ctx, config, req.Namespace, req.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if ca == nil {
+		reqLogger.V(2).Info("Secret does not belong to a CA certificate watched for refresh")
+		return ctrl.Result{}, nil
+	}
+
+	// CA secrets are usually not labelled for the operator cache, so they are
+	// read from the API server and labelled to get their updates afterwards
+	secret := &corev1.Secret{}
+	if err := r.Reader.Get(ctx, req.NamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		reqLogger.Info("Labelling CA secret to watch it for renewal")
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[res.SecretWatchLabel] = ""
+		if err := r.Client.Update(ctx, secret); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	fingerprint, err := caFingerprint(secret)
+	if err != nil {
+		reqLogger.Info("CA secret does not hold a valid certificate yet", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	current := findCARefreshStatus(config, ca)
+	bundled, overlap := trustBundlePolicy(config, ca)
+	if current != nil && current.Fingerprint == fingerprint {
+		if refreshInProgress(current) {
+			return r.continueRefresh(ctx, config, ca, *current)
+		}
+		if bundled {
+			return r.endTrustOverlap(ctx, config, ca, secret, *current)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	caStatus := operatorv1.CARefreshStatus{
+		CertName:    ca.Name,
+		Namespace:   ca.Namespace,
+		SecretName:  secret.Name,
+		Fingerprint: fingerprint,
+	}
+	if current == nil {
+		// the first version of the CA seen is only recorded, the leaf
+		// certificates were issued by it already
+		reqLogger.Info("Recording CA certificate", "fingerprint", fingerprint)
+		if bundled {
+			if err := r.publishTrustBundle(ctx, ca, secret, false); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, r.updateCARefreshStatus(ctx, caStatus)
+	}
+
+	reqLogger.Info("CA certificate changed, refreshing leaf certificates", "fingerprint", fingerprint)
+	leaves, err := r.findLeafCertificates(ctx, config, secret.Namespace, secret.Name)
+	if err != nil {
+		r.updateEvent(config, fmt.Sprintf("Failed to refresh leaf certificates of CA %s/%s: %v", ca.Namespace, ca.Name, err),
+			corev1.EventTypeWarning, "RefreshFailed")
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	caStatus.LastRefreshTime = &now
+	caStatus.QueuedCertificates = queueCertificates(leaves)
+	if refreshesChain(ca) {
+		caStatus.ChainLevel = 1
+	}
+	if bundled {
+		// the leaves only change once the bundle trusts the new CA
+		if err := r.startTrustOverlap(ctx, config, ca, secret, overlap, &caStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	r.updateEvent(config, fmt.Sprintf("CA %s/%s was renewed, refreshing %d leaf certificates", ca.Namespace, ca.Name, len(leaves)),
+		corev1.EventTypeNormal, "CARefreshing")
+
+	return r.continueRefresh(ctx, config, ca, caStatus)
+}
+
+// findLeafCertificates returns the certificates issued by the Issuers in the
+// namespace of the secret, and by the ClusterIssuers when the secret is in
+// the cluster resource namespace, that sign with the secret
+func (r *CertificateRefreshReconciler) findLeafCertificates(ctx context.Context, config *operatorv1.CertManagerConfig,
+	namespace, secretName string) ([]certmanagerv1.Certificate, error) {
+	_, leaves, err := findCAUsers(ctx, r.Client, config, namespace, secretName)
+	return leaves, err
+}
+
+// findCAUsers returns the Issuers and ClusterIssuers signing with the CA
+// secret, as Kind/namespace/name and ClusterIssuer/name, and the
+// certificates they issue
+func findCAUsers(ctx context.Context, c client.Client, config *operatorv1.CertManagerConfig,
+	namespace, secretName string) ([]string, []certmanagerv1.Certificate, error) {
+	var users []string
+	issuers := map[string]bool{}
+	issuerList := &certmanagerv1.IssuerList{}
+	if err := c.List(ctx, issuerList, client.InNamespace(namespace)); err != nil {
+		return nil, nil, err
+	}
+	for _, issuer := range issuerList.Items {
+		if issuer.Spec.CA != nil && issuer.Spec.CA.SecretName == secretName {
+			issuers[issuer.Name] = true
+			users = append(users, certmanagerv1.IssuerKind+"/"+issuer.Namespace+"/"+issuer.Name)
+		}
+	}
+
+	clusterIssuers := map[string]bool{}
+	if namespace == clusterResourceNamespace(config) {
+		clusterIssuerList := &certmanagerv1.ClusterIssuerList{}
+		if err := c.List(ctx, clusterIssuerList); err != nil {
+			return nil, nil, err
+		}
+		for _, issuer := range clusterIssuerList.Items {
+			if issuer.Spec.CA != nil && issuer.Spec.CA.SecretName == secretName {
+				clusterIssuers[issuer.Name] = true
+				users = append(users, certmanagerv1.ClusterIssuerKind+"/"+issuer.Name)
+			}
+		}
+	}
+
+	if len(issuers) == 0 && len(clusterIssuers) == 0 {
+		return nil, nil, nil
+	}
+
+	certList := &certmanagerv1.CertificateList{}
+	if err := c.List(ctx, certList); err != nil {
+		return nil, nil, err
+	}
+	var leaves []certmanagerv1.Certificate
+	for _, crt := range certList.Items {
+		ref := crt.Spec.IssuerRef
+		if ref.Group != "" && ref.Group != certmanagerv1.GroupVersion.Group {
+			continue
+		}
+		// the CA itself may be issued by one of the issuers, e.g. when it
+		// is renewed by an issuer signing with its own secret
+		if crt.Namespace == namespace && crt.Spec.SecretName == secretName {
+			continue
+		}
+		switch ref.Kind {
+		case "", certmanagerv1.IssuerKind:
+			if crt.Namespace == namespace && issuers[ref.Name] {
+				leaves = append(leaves, crt)
+			}
+		case certmanagerv1.ClusterIssuerKind:
+			if clusterIssuers[ref.Name] {
+				leaves = append(leaves, crt)
+			}
+		}
+	}
+	return users, leaves, nil
+}
+
+// findCACertificate returns the CA certificate watched for refresh that
+// stores its keypair in the given secret, or nil if there is none
+func (r *CertificateRefreshReconciler) findCACertificate(ctx context.Context, config *operatorv1.CertManagerConfig,
+	namespace, secretName string) (*certmanagerv1.Certificate, error) {
+	certList := &certmanagerv1.CertificateList{}
+	if err := r.Client.List(ctx, certList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range certList.Items {
+		crt := &certList.Items[i]
+		if crt.Spec.SecretName == secretName && isRefreshCA(config, crt) {
+			return crt, nil
+		}
+	}
+	if importedCSCA(config, namespace, secretName) {
+		if crt := importedCSCACertificate(); isRefreshCA(config, crt) {
+			return crt, nil
+		}
+	}
+	return nil, nil
+}
+
+// refreshCAs returns all the CA certificates watched for refresh, the same
+// way findCACertificate matches the secret of one
+func refreshCAs(ctx context.Context, c client.Client, config *operatorv1.CertManagerConfig) ([]certmanagerv1.Certificate, error) {
+	certList := &certmanagerv1.CertificateList{}
+	if err := c.List(ctx, certList); err != nil {
+		return nil, err
+	}
+	var cas []certmanagerv1.Certificate
+	importedFound := false
+	for i := range certList.Items {
+		crt := &certList.Items[i]
+		if !isRefreshCA(config, crt) {
+			continue
+		}
+		if importedCSCA(config, crt.Namespace, crt.Spec.SecretName) {
+			importedFound = true
+		}
+		cas = append(cas, *crt)
+	}
+	if !importedFound && importedCSCA(config, res.DeployNamespace, res.CSCASecretName) {
+		if crt := importedCSCACertificate(); isRefreshCA(config, crt) {
+			cas = append(cas, *crt)
+		}
+	}
+	return cas, nil
+}
+
+// importedCSCACertificate stands for the imported CS CA, which has no
+// certificate: it is refreshed under the name of the self-signed certificate
+// it replaced
+func importedCSCACertificate() *certmanagerv1.Certificate {
+	return &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: res.DeployNamespace},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+}
+
+// importedCSCA returns true for the CS CA secret when the CS CA is imported
+// from an external keypair
+func importedCSCA(config *operatorv1.CertManagerConfig, namespace, secretName string) bool {
+	return config.Spec.CSCA != nil && config.Spec.CSCA.Import != nil &&
+		namespace == res.DeployNamespace && secretName == res.CSCASecretName
+}
+
+// updateCARefreshStatus stores the refresh record of a CA in the status of
+// the CertManagerConfig, retrying on conflicts with the other controllers
+// writing the status
+func (r *CertificateRefreshReconciler) updateCARefreshStatus(ctx context.Context, caStatus operatorv1.CARefreshStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		config := &operatorv1.CertManagerConfig{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+			return err
+		}
+		found := false
+		for i := range config.Status.CARefresh {
+			status := &config.Status.CARefresh[i]
+			if status.CertName == caStatus.CertName && status.Namespace == caStatus.Namespace {
+				if reflect.DeepEqual(*status, caStatus) {
+					return nil
+				}
+				*status = caStatus
+				found = true
+				break
+			}
+		}
+		if !found {
+			config.Status.CARefresh = append(config.Status.CARefresh, caStatus)
+		}
+		return r.Client.Status().Update(ctx, config)
+	})
+}
+
+func (r *CertificateRefreshReconciler) updateEvent(instance runtime.Object, message, event, reason string) {
+	r.Recorder.Event(instance, event, reason, message)
+}
+
+// refreshEnabled returns true unless leaf certificate refresh is turned off
+// in the CertManagerConfig
+func refreshEnabled(config *operatorv1.CertManagerConfig) bool {
+	if config.Spec.EnableCertRefresh == nil {
+		return res.DefaultEnableCertRefresh
+	}
+	return *config.Spec.EnableCertRefresh
+}
+
+// isRefreshCA returns true if the leaf certificates of the certificate are
+// refreshed when it is renewed. The default CAs are matched in any namespace.
+func isRefreshCA(config *operatorv1.CertManagerConfig, crt *certmanagerv1.Certificate) bool {
+	if refreshesChain(crt) {
+		return true
+	}
+	for _, name := range res.DefaultCANames {
+		if crt.Name == name {
+			return true
+		}
+	}
+	for _, ca := range config.Spec.RefreshCertsBasedOnCA {
+		if crt.Name == ca.CertName && crt.Namespace == ca.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshesChain returns true if the whole chain below the CA certificate is
+// refreshed level by level when it is renewed
+func refreshesChain(crt *certmanagerv1.Certificate) bool {
+	return crt.Labels[res.RefreshCALabel] == "true"
+}
+
+// refreshCompleted returns true once the certificate is Ready with a newer
+// revision than the one it had when its re-issuance was requested
+func refreshCompleted(crt *certmanagerv1.Certificate, revision int) bool {
+	return !util.IsCertificateIssuing(crt) && util.IsCertificateReady(crt) &&
+		crt.Status.Revision != nil && *crt.Status.Revision > revision
+}
+
+// clusterResourceNamespace returns the namespace the ClusterIssuers read
+// their secrets from
+func clusterResourceNamespace(config *operatorv1.CertManagerConfig) string {
+	if config.Spec.ResourceNS != "" {
+		return config.Spec.ResourceNS
+	}
+	return res.DeployNamespace
+}
+
+func findCARefreshStatus(config *operatorv1.CertManagerConfig, ca *certmanagerv1.Certificate) *operatorv1.CARefreshStatus {
+	for i := range config.Status.CARefresh {
+		status := &config.Status.CARefresh[i]
+		if status.CertName == ca.Name && status.Namespace == ca.Namespace {
+			return status
+		}
+	}
+	return nil
+}
+
+// caFingerprint returns the SHA-256 fingerprint of the first certificate in
+// the tls.crt of the secret
+func caFingerprint(secret *corev1.Secret) (string, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("no PEM certificate in %s", corev1.TLSCertKey)
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(block.Bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificateRefreshReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("certificaterefresh-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch the labelled CA secrets for a new tls.crt
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForObject{}, predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data[corev1.TLSCertKey], newSecret.Data[corev1.TLSCertKey])
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch the CA certificates, to pick up their secrets before they are
+	// labelled
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.Certificate{}}, handler.EnqueueRequestsFromMapFunc(r.caSecretForCertificate))
+	if err != nil {
+		return err
+	}
+
+	// Watch the CertManagerConfig for changes to the list of CAs
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertManagerConfig{}}, handler.EnqueueRequestsFromMapFunc(r.allCASecrets),
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *CertificateRefreshReconciler) caSecretForCertificate(obj client.Object) []reconcile.Request {
+	crt, ok := obj.(*certmanagerv1.Certificate)
+	if !ok {
+		return nil
+	}
+	config := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		return nil
+	}
+	if !isRefreshCA(config, crt) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: crt.Namespace, Name: crt.Spec.SecretName}}}
+}
+
+func (r *CertificateRefreshReconciler) allCASecrets(obj client.Object) []reconcile.Request {
+	config, ok := obj.(*operatorv1.CertManagerConfig)
+	if !ok || config.Name != res.CertManagerInstanceName {
+		return nil
+	}
+	certList := &certmanagerv1.CertificateList{}
+	if err := r.Client.List(context.TODO(), certList); err != nil {
+		logd.Error(err, "Failed to list certificates")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range certList.Items {
+		crt := &certList.Items[i]
+		if isRefreshCA(config, crt) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: crt.Namespace, Name: crt.Spec.SecretName}})
+		}
+	}
+	return requests
+}
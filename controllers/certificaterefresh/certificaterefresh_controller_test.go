@@ -0,0 +1,366 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+const testNS = "ibm-common-services"
+
+func selfSignedPEM(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newReconciler(t *testing.T, objs ...client.Object) *CertificateRefreshReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, operatorv1.AddToScheme, certmanagerv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &CertificateRefreshReconciler{
+		Client:   c,
+		Reader:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func TestReconcileRefreshesLeafCertificates(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	ca := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "old")},
+	}
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: res.CSCASecretName},
+		}},
+	}
+	leaf := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: testNS},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "leaf-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: res.CSCAIssuerName, Kind: certmanagerv1.IssuerKind},
+		},
+	}
+	other := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNS},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "other-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: "other-issuer", Kind: certmanagerv1.IssuerKind},
+		},
+	}
+
+	r := newReconciler(t, config, ca, secret, issuer, leaf, other)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}}
+
+	// first reconcile only records the CA
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Status.CARefresh) != 1 || config.Status.CARefresh[0].Fingerprint == "" || config.Status.CARefresh[0].LastRefreshTime != nil {
+		t.Fatalf("unexpected refresh status after first reconcile: %+v", config.Status.CARefresh)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("CA secret not labelled with %s", res.SecretWatchLabel)
+	}
+	assertIssuing(t, r, leaf, false)
+
+	// renewing the CA refreshes the leaf certificates of its issuer only
+	secret.Data[corev1.TLSCertKey] = selfSignedPEM(t, "new")
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertIssuing(t, r, leaf, true)
+	assertIssuing(t, r, other, false)
+	assertIssuing(t, r, ca, false)
+
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	status := config.Status.CARefresh[0]
+	if status.LastRefreshTime == nil || len(status.RefreshedCertificates) != 1 || status.RefreshedCertificates[0] != testNS+"/leaf" {
+		t.Errorf("unexpected refresh status after renewal: %+v", status)
+	}
+}
+
+func TestReconcileIgnoresUnwatchedSecrets(t *testing.T) {
+	disabled := false
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec:       operatorv1.CertManagerConfigSpec{EnableCertRefresh: &disabled},
+	}
+	ca := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "ca")},
+	}
+
+	r := newReconciler(t, config, ca, secret)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}}
+	if _, err := r.Reconcile(context.TODO(), req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Status.CARefresh) != 0 {
+		t.Errorf("refresh status recorded while refresh is disabled: %+v", config.Status.CARefresh)
+	}
+}
+
+func assertIssuing(t *testing.T, r *CertificateRefreshReconciler, crt *certmanagerv1.Certificate, want bool) {
+	t.Helper()
+	got := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(crt), got); err != nil {
+		t.Fatal(err)
+	}
+	if util.IsCertificateIssuing(got) != want {
+		t.Errorf("certificate %s issuing = %v, want %v", crt.Name, !want, want)
+	}
+}
+
+func TestReconcileRefreshesCAChainLevelByLevel(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			RefreshPolicy: &operatorv1.CertRefreshPolicy{Interval: &metav1.Duration{}},
+		},
+	}
+	root := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "root-ca", Namespace: testNS, Labels: map[string]string{res.RefreshCALabel: "true"}},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: "root-ca-secret", IsCA: true},
+	}
+	rootSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "root-ca-secret", Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "root")},
+	}
+	caIssuer := func(name, secretName string) *certmanagerv1.Issuer {
+		return &certmanagerv1.Issuer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+			Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+				CA: &certmanagerv1.CAIssuer{SecretName: secretName},
+			}},
+		}
+	}
+	intermediate := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "intermediate-ca", Namespace: testNS},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "intermediate-ca-secret",
+			IsCA:       true,
+			IssuerRef:  cmmeta.ObjectReference{Name: "root-issuer"},
+		},
+	}
+	leaf := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: testNS},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "leaf-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: "intermediate-issuer", Kind: certmanagerv1.IssuerKind},
+		},
+	}
+
+	r := newReconciler(t, config, root, rootSecret, caIssuer("root-issuer", "root-ca-secret"),
+		intermediate, caIssuer("intermediate-issuer", "intermediate-ca-secret"), leaf)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: "root-ca-secret"}}
+	reconcile := func() ctrl.Result {
+		t.Helper()
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	chainLevel := func() int {
+		t.Helper()
+		config = &operatorv1.CertManagerConfig{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+			t.Fatal(err)
+		}
+		return config.Status.CARefresh[0].ChainLevel
+	}
+	// reissued plays the part of cert-manager completing an issuance
+	reissued := func(crt *certmanagerv1.Certificate) {
+		t.Helper()
+		got := &certmanagerv1.Certificate{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(crt), got); err != nil {
+			t.Fatal(err)
+		}
+		revision := 2
+		got.Status.Revision = &revision
+		util.SetCertificateCondition(got, certmanagerv1.CertificateConditionIssuing, cmmeta.ConditionFalse, "Issued", "")
+		util.SetCertificateCondition(got, certmanagerv1.CertificateConditionReady, cmmeta.ConditionTrue, "Ready", "")
+		if err := r.Client.Status().Update(ctx, got); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconcile()
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(rootSecret), rootSecret); err != nil {
+		t.Fatal(err)
+	}
+	rootSecret.Data[corev1.TLSCertKey] = selfSignedPEM(t, "new root")
+	if err := r.Client.Update(ctx, rootSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	if reconcile().RequeueAfter == 0 || chainLevel() != 1 {
+		t.Fatalf("expected level 1 of the chain to be refreshing, got level %d", chainLevel())
+	}
+	assertIssuing(t, r, intermediate, true)
+	assertIssuing(t, r, leaf, false)
+
+	// the leaves wait for the intermediate CA to be re-issued
+	if reconcile().RequeueAfter == 0 || chainLevel() != 1 {
+		t.Fatalf("expected to wait on level 1, got level %d", chainLevel())
+	}
+	assertIssuing(t, r, leaf, false)
+
+	reissued(intermediate)
+	if reconcile().RequeueAfter == 0 || chainLevel() != 2 {
+		t.Fatalf("expected level 2 of the chain to be refreshing, got level %d", chainLevel())
+	}
+	assertIssuing(t, r, leaf, true)
+
+	reissued(leaf)
+	if reconcile().RequeueAfter != 0 || chainLevel() != 0 {
+		t.Fatalf("expected the chain refresh to be complete, got level %d", chainLevel())
+	}
+	want := []string{testNS + "/intermediate-ca", testNS + "/leaf"}
+	if got := config.Status.CARefresh[0].RefreshedCertificates; !reflect.DeepEqual(got, want) {
+		t.Errorf("got refreshed certificates %v, want %v", got, want)
+	}
+}
+
+func TestReconcileRefreshesImportedCSCA(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{Import: &operatorv1.CSCAImport{SecretName: "corporate-ca"}},
+		},
+	}
+	// the imported CS CA has no certificate
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: res.DeployNamespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "self-signed")},
+	}
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: res.DeployNamespace},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: res.CSCASecretName},
+		}},
+	}
+	leaf := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: res.DeployNamespace},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "leaf-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: res.CSCAIssuerName, Kind: certmanagerv1.IssuerKind},
+		},
+	}
+
+	r := newReconciler(t, config, secret, issuer, leaf)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: res.DeployNamespace, Name: res.CSCASecretName}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	secret.Data[corev1.TLSCertKey] = selfSignedPEM(t, "corporate")
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertIssuing(t, r, leaf, true)
+
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Status.CARefresh) != 1 || config.Status.CARefresh[0].CertName != res.CSCACertName {
+		t.Errorf("unexpected refresh status %+v", config.Status.CARefresh)
+	}
+}
@@ -0,0 +1,230 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// previewInterval is how often the refresh preview is recomputed while it is
+// enabled, to follow changes to issuers and certificates
+const previewInterval = 5 * time.Minute
+
+// previewConfigMapKey is the key of the ConfigMap holding the preview
+const previewConfigMapKey = "preview.json"
+
+// RefreshPreviewReconciler publishes, for every CA watched for refresh, the
+// issuers, certificates and secrets its next refresh would touch. It never
+// modifies them.
+type RefreshPreviewReconciler struct {
+	Client client.Client
+	Reader client.Reader
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+
+// Reconcile computes the refresh preview and stores it in the status of the
+// CertManagerConfig and, if asked for, in a ConfigMap
+func (r *RefreshPreviewReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Name", req.Name)
+
+	config := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, req.NamespacedName, config); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	preview := config.Spec.RefreshPreview
+	if preview == nil || !preview.Enabled {
+		if len(config.Status.RefreshPreview) == 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, r.updatePreviewStatus(ctx, nil)
+	}
+
+	// the CAs are selected as the refresh selects them: the default CAs, the
+	// CAs labelled to refresh their chain and the ones listed
+	cas, err := refreshCAs(ctx, r.Client, config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var previews []operatorv1.CARefreshPreview
+	found := map[string]bool{}
+	for i := range cas {
+		p, err := r.previewCA(ctx, config, &cas[i])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		found[cas[i].Namespace+"/"+cas[i].Name] = true
+		previews = append(previews, p)
+	}
+	for _, entry := range config.Spec.RefreshCertsBasedOnCA {
+		if !found[entry.Namespace+"/"+entry.CertName] {
+			previews = append(previews, operatorv1.CARefreshPreview{CertName: entry.CertName, Namespace: entry.Namespace,
+				Error: "CA certificate not found"})
+		}
+	}
+	reqLogger.V(2).Info("Computed refresh preview", "CAs", len(previews))
+
+	if err := r.updatePreviewStatus(ctx, previews); err != nil {
+		return ctrl.Result{}, err
+	}
+	if preview.ConfigMapName != "" {
+		if err := r.publishPreview(ctx, config, preview.ConfigMapName, previews); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: previewInterval}, nil
+}
+
+// previewCA walks the issuers signing with the CA, down its chain when the CA
+// is refreshed as a chain, the same way a refresh would
+func (r *RefreshPreviewReconciler) previewCA(ctx context.Context, config *operatorv1.CertManagerConfig,
+	ca *certmanagerv1.Certificate) (operatorv1.CARefreshPreview, error) {
+	p := operatorv1.CARefreshPreview{CertName: ca.Name, Namespace: ca.Namespace}
+
+	issuers, certificates, secrets := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	level := []*certmanagerv1.Certificate{ca}
+	for depth := 0; len(level) > 0 && depth < maxChainDepth; depth++ {
+		var next []*certmanagerv1.Certificate
+		for _, signer := range level {
+			users, leaves, err := findCAUsers(ctx, r.Client, config, signer.Namespace, signer.Spec.SecretName)
+			if err != nil {
+				return p, err
+			}
+			for _, issuer := range users {
+				issuers[issuer] = true
+			}
+			for i := range leaves {
+				leaf := &leaves[i]
+				key := leaf.Namespace + "/" + leaf.Name
+				if certificates[key] {
+					continue
+				}
+				certificates[key] = true
+				secrets[leaf.Namespace+"/"+leaf.Spec.SecretName] = true
+				if refreshesChain(ca) && leaf.Spec.IsCA {
+					next = append(next, leaf)
+				}
+			}
+		}
+		level = next
+	}
+
+	p.Issuers = sortedKeys(issuers)
+	p.Certificates = sortedKeys(certificates)
+	p.Secrets = sortedKeys(secrets)
+	return p, nil
+}
+
+func (r *RefreshPreviewReconciler) updatePreviewStatus(ctx context.Context, previews []operatorv1.CARefreshPreview) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		config := &operatorv1.CertManagerConfig{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(config.Status.RefreshPreview, previews) {
+			return nil
+		}
+		config.Status.RefreshPreview = previews
+		return r.Client.Status().Update(ctx, config)
+	})
+}
+
+// publishPreview writes the preview as JSON to the ConfigMap in the
+// operator namespace, owned by the CertManagerConfig
+func (r *RefreshPreviewReconciler) publishPreview(ctx context.Context, config *operatorv1.CertManagerConfig, name string,
+	previews []operatorv1.CARefreshPreview) error {
+	data, err := json.MarshalIndent(previews, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.Reader.Get(ctx, types.NamespacedName{Namespace: res.DeployNamespace, Name: name}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: res.DeployNamespace},
+			Data:       map[string]string{previewConfigMapKey: string(data)},
+		}
+		if err := controllerutil.SetControllerReference(config, cm, r.Scheme); err != nil {
+			return err
+		}
+		logd.Info("Creating refresh preview ConfigMap", "name", name)
+		return r.Client.Create(ctx, cm)
+	} else if err != nil {
+		return err
+	}
+
+	if cm.Data[previewConfigMapKey] == string(data) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[previewConfigMapKey] = string(data)
+	return r.Client.Update(ctx, cm)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RefreshPreviewReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("refreshpreview-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch the CertManagerConfig for the preview to be turned on or the CAs to change
+	return c.Watch(&source.Kind{Type: &operatorv1.CertManagerConfig{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{})
+}
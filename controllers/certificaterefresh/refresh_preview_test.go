@@ -0,0 +1,154 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestRefreshPreview(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			RefreshCertsBasedOnCA: []operatorv1.CACertificate{
+				{CertName: "root-ca", Namespace: testNS},
+				{CertName: "missing-ca", Namespace: testNS},
+			},
+			RefreshPreview: &operatorv1.CertRefreshPreview{Enabled: true, ConfigMapName: "refresh-preview"},
+		},
+	}
+	root := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "root-ca", Namespace: testNS, Labels: map[string]string{res.RefreshCALabel: "true"}},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: "root-ca-secret", IsCA: true},
+	}
+	caIssuer := func(name, secretName string) *certmanagerv1.Issuer {
+		return &certmanagerv1.Issuer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+			Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+				CA: &certmanagerv1.CAIssuer{SecretName: secretName},
+			}},
+		}
+	}
+	issued := func(name, issuer string, isCA bool) *certmanagerv1.Certificate {
+		return &certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: name + "-secret",
+				IsCA:       isCA,
+				IssuerRef:  cmmeta.ObjectReference{Name: issuer},
+			},
+		}
+	}
+
+	r := &RefreshPreviewReconciler{}
+	refresh := newReconciler(t, config, root, caIssuer("root-issuer", "root-ca-secret"),
+		issued("intermediate-ca", "root-issuer", true), caIssuer("intermediate-issuer", "intermediate-ca-secret"),
+		issued("leaf", "intermediate-issuer", false), issued("unrelated", "other-issuer", false))
+	r.Client, r.Reader, r.Scheme = refresh.Client, refresh.Reader, refresh.Scheme
+
+	ctx := context.TODO()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []operatorv1.CARefreshPreview{
+		{
+			CertName:     "root-ca",
+			Namespace:    testNS,
+			Issuers:      []string{"Issuer/" + testNS + "/intermediate-issuer", "Issuer/" + testNS + "/root-issuer"},
+			Certificates: []string{testNS + "/intermediate-ca", testNS + "/leaf"},
+			Secrets:      []string{testNS + "/intermediate-ca-secret", testNS + "/leaf-secret"},
+		},
+		{CertName: "missing-ca", Namespace: testNS, Error: "CA certificate not found"},
+	}
+
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config.Status.RefreshPreview, want) {
+		t.Errorf("got preview %+v, want %+v", config.Status.RefreshPreview, want)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: res.DeployNamespace, Name: "refresh-preview"}, cm); err != nil {
+		t.Fatal(err)
+	}
+	var published []operatorv1.CARefreshPreview
+	if err := json.Unmarshal([]byte(cm.Data[previewConfigMapKey]), &published); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("got published preview %+v, want %+v", published, want)
+	}
+
+	// nothing is re-issued by the preview
+	for _, name := range []string{"intermediate-ca", "leaf"} {
+		assertIssuing(t, refresh, &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS}}, false)
+	}
+}
+
+func TestRefreshPreviewSelectsLikeRefresh(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			RefreshPreview: &operatorv1.CertRefreshPreview{Enabled: true, ConfigMapName: "refresh-preview"},
+		},
+	}
+	ca := func(name, namespace string, labels map[string]string) *certmanagerv1.Certificate {
+		return &certmanagerv1.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       certmanagerv1.CertificateSpec{SecretName: name + "-secret", IsCA: true},
+		}
+	}
+
+	r := &RefreshPreviewReconciler{}
+	refresh := newReconciler(t, config, ca(res.DefaultCANames[0], "other", nil),
+		ca("chain-ca", testNS, map[string]string{res.RefreshCALabel: "true"}), ca("ignored-ca", testNS, nil))
+	r.Client, r.Reader, r.Scheme = refresh.Client, refresh.Reader, refresh.Scheme
+
+	ctx := context.TODO()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}); err != nil {
+		t.Fatal(err)
+	}
+
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range config.Status.RefreshPreview {
+		got = append(got, p.Namespace+"/"+p.CertName)
+	}
+	want := []string{testNS + "/chain-ca", "other/" + res.DefaultCANames[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got previews for %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,283 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// continueRefresh moves the refresh of a CA forward by one step. Certificates
+// done re-issuing leave the pending list, as do the ones that time out, which
+// are reported as failed. Queued certificates start re-issuing while the
+// policy allows it and, for a CA chain, the next level is queued once the
+// current one is done. The progress is kept in the status
+// of the CertManagerConfig, so a restarted operator picks up where it was.
+func (r *CertificateRefreshReconciler) continueRefresh(ctx context.Context, config *operatorv1.CertManagerConfig,
+	ca *certmanagerv1.Certificate, caStatus operatorv1.CARefreshStatus) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("CA.Namespace", ca.Namespace, "CA.Name", ca.Name, "ChainLevel", caStatus.ChainLevel)
+	maxConcurrent, interval := refreshPolicy(config)
+	timeout := refreshTimeout(config)
+	now := time.Now()
+
+	pending := []operatorv1.PendingCertificate{}
+	for _, p := range caStatus.PendingCertificates {
+		crt := &certmanagerv1.Certificate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: p.Namespace, Name: p.Name}, crt); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if !refreshCompleted(crt, p.Revision) {
+			if p.StartTime == nil {
+				startTime := metav1.NewTime(now)
+				p.StartTime = &startTime
+			}
+			if now.Sub(p.StartTime.Time) < timeout {
+				pending = append(pending, p)
+				continue
+			}
+			reqLogger.Info("Giving up on re-issuing certificate", "Certificate", p.Namespace+"/"+p.Name, "timeout", timeout)
+			message := fmt.Sprintf("Certificate %s/%s was not re-issued within %s after CA %s/%s was renewed", p.Namespace, p.Name, timeout, ca.Namespace, ca.Name)
+			r.updateEvent(config, message, corev1.EventTypeWarning, "RefreshTimedOut")
+			r.Recorder.Event(crt, corev1.EventTypeWarning, "RefreshTimedOut", message)
+			caStatus.FailedCertificates = append(caStatus.FailedCertificates, p.Namespace+"/"+p.Name)
+			continue
+		}
+		if caStatus.ChainLevel > 0 && crt.Spec.IsCA {
+			caStatus.LevelCAs = append(caStatus.LevelCAs, p.Namespace+"/"+p.Name)
+		}
+	}
+	caStatus.PendingCertificates = pending
+
+	if caStatus.ChainLevel > 0 && len(caStatus.PendingCertificates) == 0 && len(caStatus.QueuedCertificates) == 0 &&
+		len(caStatus.LevelCAs) > 0 {
+		next, err := r.nextChainLevel(ctx, config, caStatus.LevelCAs)
+		if err != nil {
+			r.updateEvent(config, fmt.Sprintf("Failed to refresh level %d of the chain of CA %s/%s: %v", caStatus.ChainLevel+1, ca.Namespace, ca.Name, err),
+				corev1.EventTypeWarning, "RefreshFailed")
+			return ctrl.Result{}, err
+		}
+		caStatus.LevelCAs = nil
+		if len(next) > 0 && caStatus.ChainLevel < maxChainDepth {
+			caStatus.ChainLevel++
+			caStatus.QueuedCertificates = queueCertificates(next)
+			r.updateEvent(config, fmt.Sprintf("Refreshing level %d of the chain of CA %s/%s, %d certificates", caStatus.ChainLevel, ca.Namespace, ca.Name, len(next)),
+				corev1.EventTypeNormal, "CAChainRefreshing")
+		}
+	}
+
+	for len(caStatus.QueuedCertificates) > 0 && len(caStatus.PendingCertificates) < maxConcurrent {
+		if caStatus.NextRefreshTime != nil && now.Before(caStatus.NextRefreshTime.Time) {
+			break
+		}
+		queued := caStatus.QueuedCertificates[0]
+		caStatus.QueuedCertificates = caStatus.QueuedCertificates[1:]
+
+		crt := &certmanagerv1.Certificate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: queued.Namespace, Name: queued.Name}, crt); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		if err := r.reissueCertificate(ctx, crt, fmt.Sprintf("Re-issuing certificate as CA %s/%s was renewed", ca.Namespace, ca.Name)); err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(2).Info("Re-issuing certificate", "Certificate", queued.Namespace+"/"+queued.Name)
+		p := pendingCertificate(crt)
+		startTime := metav1.NewTime(now)
+		p.StartTime = &startTime
+		caStatus.PendingCertificates = append(caStatus.PendingCertificates, p)
+		caStatus.RefreshedCertificates = append(caStatus.RefreshedCertificates, queued.Namespace+"/"+queued.Name)
+		if interval > 0 {
+			nextRefresh := metav1.NewTime(now.Add(interval))
+			caStatus.NextRefreshTime = &nextRefresh
+		}
+	}
+
+	if !refreshInProgress(&caStatus) {
+		reqLogger.Info("CA refresh completed", "certificates", len(caStatus.RefreshedCertificates), "failed", len(caStatus.FailedCertificates))
+		caStatus.ChainLevel = 0
+		caStatus.NextRefreshTime = nil
+		if err := r.updateCARefreshStatus(ctx, caStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(caStatus.FailedCertificates) > 0 {
+			r.updateEvent(config, fmt.Sprintf("CA %s/%s was renewed, refreshed %d leaf certificates, %d of which were not re-issued in time: %s",
+				ca.Namespace, ca.Name, len(caStatus.RefreshedCertificates), len(caStatus.FailedCertificates), strings.Join(caStatus.FailedCertificates, ", ")),
+				corev1.EventTypeWarning, "CARefreshIncomplete")
+		} else {
+			r.updateEvent(config, fmt.Sprintf("CA %s/%s was renewed, refreshed %d leaf certificates", ca.Namespace, ca.Name, len(caStatus.RefreshedCertificates)),
+				corev1.EventTypeNormal, "CARefreshed")
+		}
+		// come back to drop the previous CA from the trust bundle
+		if caStatus.TrustOverlapUntil != nil {
+			if wait := time.Until(caStatus.TrustOverlapUntil.Time); wait > 0 {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.updateCARefreshStatus(ctx, caStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	requeueAfter := refreshCheckInterval
+	if len(caStatus.QueuedCertificates) > 0 && len(caStatus.PendingCertificates) < maxConcurrent && caStatus.NextRefreshTime != nil {
+		if wait := caStatus.NextRefreshTime.Sub(now); wait < requeueAfter {
+			requeueAfter = wait
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// nextChainLevel returns the certificates issued by the CAs, given as
+// namespace/name, of the level of the chain that was just refreshed
+func (r *CertificateRefreshReconciler) nextChainLevel(ctx context.Context, config *operatorv1.CertManagerConfig,
+	levelCAs []string) ([]certmanagerv1.Certificate, error) {
+	var next []certmanagerv1.Certificate
+	seen := map[string]bool{}
+	for _, key := range levelCAs {
+		namespace, name := splitKey(key)
+		crt := &certmanagerv1.Certificate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, crt); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		leaves, err := r.findLeafCertificates(ctx, config, crt.Namespace, crt.Spec.SecretName)
+		if err != nil {
+			return nil, err
+		}
+		for _, leaf := range leaves {
+			if leafKey := leaf.Namespace + "/" + leaf.Name; !seen[leafKey] {
+				seen[leafKey] = true
+				next = append(next, leaf)
+			}
+		}
+	}
+	return next, nil
+}
+
+// reissueCertificate asks cert-manager to re-issue the certificate, unless
+// an issuance is already in progress
+func (r *CertificateRefreshReconciler) reissueCertificate(ctx context.Context, crt *certmanagerv1.Certificate, message string) error {
+	if util.IsCertificateIssuing(crt) {
+		return nil
+	}
+	util.SetCertificateCondition(crt, certmanagerv1.CertificateConditionIssuing, cmmeta.ConditionTrue, CARenewedReason, message)
+	if err := r.Client.Status().Update(ctx, crt); err != nil {
+		return err
+	}
+	r.Recorder.Event(crt, corev1.EventTypeNormal, CARenewedReason, message)
+	return nil
+}
+
+// queueCertificates orders the certificates for re-issuance, taking one
+// certificate from each namespace in turn so that no namespace waits for all
+// the certificates of another
+func queueCertificates(crts []certmanagerv1.Certificate) []operatorv1.PendingCertificate {
+	byNamespace := map[string][]operatorv1.PendingCertificate{}
+	var namespaces []string
+	for _, crt := range crts {
+		if _, ok := byNamespace[crt.Namespace]; !ok {
+			namespaces = append(namespaces, crt.Namespace)
+		}
+		byNamespace[crt.Namespace] = append(byNamespace[crt.Namespace], pendingCertificate(&crt))
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		sort.Slice(byNamespace[ns], func(i, j int) bool { return byNamespace[ns][i].Name < byNamespace[ns][j].Name })
+	}
+
+	queue := make([]operatorv1.PendingCertificate, 0, len(crts))
+	for len(queue) < len(crts) {
+		for _, ns := range namespaces {
+			if len(byNamespace[ns]) == 0 {
+				continue
+			}
+			queue = append(queue, byNamespace[ns][0])
+			byNamespace[ns] = byNamespace[ns][1:]
+		}
+	}
+	return queue
+}
+
+func pendingCertificate(crt *certmanagerv1.Certificate) operatorv1.PendingCertificate {
+	p := operatorv1.PendingCertificate{Name: crt.Name, Namespace: crt.Namespace}
+	if crt.Status.Revision != nil {
+		p.Revision = *crt.Status.Revision
+	}
+	return p
+}
+
+// refreshInProgress returns true while certificates of the CA are queued or
+// being re-issued, or the next level of its chain is still to be queued
+func refreshInProgress(caStatus *operatorv1.CARefreshStatus) bool {
+	return len(caStatus.QueuedCertificates) > 0 || len(caStatus.PendingCertificates) > 0 || len(caStatus.LevelCAs) > 0
+}
+
+// refreshPolicy returns the number of certificates re-issued at the same
+// time and the minimum time between starting two re-issuances
+func refreshPolicy(config *operatorv1.CertManagerConfig) (int, time.Duration) {
+	maxConcurrent, interval := res.DefaultRefreshMaxConcurrent, res.DefaultRefreshInterval
+	if policy := config.Spec.RefreshPolicy; policy != nil {
+		if policy.MaxConcurrent > 0 {
+			maxConcurrent = policy.MaxConcurrent
+		}
+		if policy.Interval != nil && policy.Interval.Duration >= 0 {
+			interval = policy.Interval.Duration
+		}
+	}
+	return maxConcurrent, interval
+}
+
+// refreshTimeout returns how long the re-issuance of a certificate is waited
+// for
+func refreshTimeout(config *operatorv1.CertManagerConfig) time.Duration {
+	if policy := config.Spec.RefreshPolicy; policy != nil && policy.Timeout != nil && policy.Timeout.Duration > 0 {
+		return policy.Timeout.Duration
+	}
+	return res.DefaultRefreshTimeout
+}
+
+func splitKey(key string) (string, string) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 {
+		return "", key
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,225 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func clusterIssued(namespace, name string) *certmanagerv1.Certificate {
+	return &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: name + "-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: "cs-ca-clusterissuer", Kind: certmanagerv1.ClusterIssuerKind},
+		},
+	}
+}
+
+func TestQueueCertificatesInterleavesNamespaces(t *testing.T) {
+	crts := []certmanagerv1.Certificate{
+		*clusterIssued("b", "b2"), *clusterIssued("a", "a3"), *clusterIssued("a", "a1"),
+		*clusterIssued("b", "b1"), *clusterIssued("a", "a2"), *clusterIssued("c", "c1"),
+	}
+	var got []string
+	for _, p := range queueCertificates(crts) {
+		got = append(got, p.Namespace+"/"+p.Name)
+	}
+	want := []string{"a/a1", "b/b1", "c/c1", "a/a2", "b/b2", "a/a3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got queue %v, want %v", got, want)
+	}
+}
+
+func TestRefreshPolicy(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{}
+	if maxConcurrent, interval := refreshPolicy(config); maxConcurrent != res.DefaultRefreshMaxConcurrent || interval != res.DefaultRefreshInterval {
+		t.Errorf("got defaults %d, %s", maxConcurrent, interval)
+	}
+	config.Spec.RefreshPolicy = &operatorv1.CertRefreshPolicy{MaxConcurrent: 3, Interval: &metav1.Duration{}}
+	if maxConcurrent, interval := refreshPolicy(config); maxConcurrent != 3 || interval != 0 {
+		t.Errorf("got %d, %s, want 3, 0s", maxConcurrent, interval)
+	}
+}
+
+func TestReconcileLimitsConcurrentRefreshes(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			ResourceNS:    testNS,
+			RefreshPolicy: &operatorv1.CertRefreshPolicy{MaxConcurrent: 2, Interval: &metav1.Duration{}},
+		},
+	}
+	ca := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "old")},
+	}
+	clusterIssuer := &certmanagerv1.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cs-ca-clusterissuer"},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: res.CSCASecretName},
+		}},
+	}
+	a1, a2, a3, b1 := clusterIssued("a", "a1"), clusterIssued("a", "a2"), clusterIssued("a", "a3"), clusterIssued("b", "b1")
+
+	r := newReconciler(t, config, ca, secret, clusterIssuer, a1, a2, a3, b1)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}}
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconcile()
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	secret.Data[corev1.TLSCertKey] = selfSignedPEM(t, "new")
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcile()
+	assertIssuing(t, r, a1, true)
+	assertIssuing(t, r, b1, true)
+	assertIssuing(t, r, a2, false)
+	assertIssuing(t, r, a3, false)
+
+	// nothing new starts while both slots are busy
+	reconcile()
+	assertIssuing(t, r, a2, false)
+
+	got := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(a1), got); err != nil {
+		t.Fatal(err)
+	}
+	revision := 1
+	got.Status.Revision = &revision
+	util.SetCertificateCondition(got, certmanagerv1.CertificateConditionIssuing, cmmeta.ConditionFalse, "Issued", "")
+	util.SetCertificateCondition(got, certmanagerv1.CertificateConditionReady, cmmeta.ConditionTrue, "Ready", "")
+	if err := r.Client.Status().Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcile()
+	assertIssuing(t, r, a2, true)
+	assertIssuing(t, r, a3, false)
+
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	status := config.Status.CARefresh[0]
+	if len(status.PendingCertificates) != 2 || len(status.QueuedCertificates) != 1 || status.QueuedCertificates[0].Name != "a3" {
+		t.Errorf("unexpected refresh progress %+v", status)
+	}
+}
+
+func TestReconcileTimesOutStuckRefresh(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			ResourceNS: testNS,
+			RefreshPolicy: &operatorv1.CertRefreshPolicy{MaxConcurrent: 1, Interval: &metav1.Duration{},
+				Timeout: &metav1.Duration{Duration: time.Minute}},
+		},
+	}
+	ca := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: selfSignedPEM(t, "old")},
+	}
+	clusterIssuer := &certmanagerv1.ClusterIssuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cs-ca-clusterissuer"},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: res.CSCASecretName},
+		}},
+	}
+	a1, a2 := clusterIssued("a", "a1"), clusterIssued("a", "a2")
+
+	r := newReconciler(t, config, ca, secret, clusterIssuer, a1, a2)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}}
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconcile()
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	secret.Data[corev1.TLSCertKey] = selfSignedPEM(t, "new")
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	reconcile()
+	assertIssuing(t, r, a1, true)
+	assertIssuing(t, r, a2, false)
+
+	// a1 never becomes Ready with a new revision
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	started := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	config.Status.CARefresh[0].PendingCertificates[0].StartTime = &started
+	if err := r.Client.Status().Update(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+
+	reconcile()
+	assertIssuing(t, r, a2, true)
+
+	config = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		t.Fatal(err)
+	}
+	status := config.Status.CARefresh[0]
+	if len(status.FailedCertificates) != 1 || status.FailedCertificates[0] != "a/a1" {
+		t.Errorf("timed out certificate not reported: %+v", status)
+	}
+	if len(status.PendingCertificates) != 1 || status.PendingCertificates[0].Name != "a2" {
+		t.Errorf("unexpected refresh progress %+v", status)
+	}
+}
@@ -0,0 +1,178 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// bundlePropagationDelay is how long leaf certificates wait after the trust
+// bundle of a rotated CA was published, so that the kubelets have updated
+// the mounted copies of the bundle before the leaves change
+const bundlePropagationDelay = time.Minute
+
+// trustBundlePolicy returns whether a trust bundle is published for the CA,
+// and for how long it keeps the previous CA after a rotation
+func trustBundlePolicy(config *operatorv1.CertManagerConfig, ca *certmanagerv1.Certificate) (bool, time.Duration) {
+	policy := config.Spec.CATrustBundle
+	if policy == nil || !policy.Enabled {
+		return false, 0
+	}
+	listed := false
+	for _, entry := range config.Spec.RefreshCertsBasedOnCA {
+		if entry.CertName == ca.Name && entry.Namespace == ca.Namespace {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return false, 0
+	}
+	if policy.OverlapDuration != nil {
+		return true, policy.OverlapDuration.Duration
+	}
+	return true, res.DefaultTrustOverlapDuration
+}
+
+// startTrustOverlap publishes the trust bundle of a rotated CA with both the
+// previous and the new CA, and holds back the leaf certificates until the
+// bundle has propagated
+func (r *CertificateRefreshReconciler) startTrustOverlap(ctx context.Context, config *operatorv1.CertManagerConfig,
+	ca *certmanagerv1.Certificate, secret *corev1.Secret, overlap time.Duration, caStatus *operatorv1.CARefreshStatus) error {
+	if err := r.publishTrustBundle(ctx, ca, secret, true); err != nil {
+		r.updateEvent(config, fmt.Sprintf("Failed to publish the trust bundle of CA %s/%s: %v", ca.Namespace, ca.Name, err),
+			corev1.EventTypeWarning, "TrustBundleFailed")
+		return err
+	}
+	now := time.Now()
+	overlapUntil := metav1.NewTime(now.Add(overlap))
+	nextRefresh := metav1.NewTime(now.Add(bundlePropagationDelay))
+	caStatus.TrustOverlapUntil = &overlapUntil
+	caStatus.NextRefreshTime = &nextRefresh
+	r.updateEvent(config, fmt.Sprintf("Trust bundle of CA %s/%s holds the previous and the new CA until %s", ca.Namespace, ca.Name, overlapUntil.Format(time.RFC3339)),
+		corev1.EventTypeNormal, "TrustBundlePublished")
+	return nil
+}
+
+// endTrustOverlap keeps the trust bundle of the CA published and drops the
+// previous CA from it once the overlap window is over
+func (r *CertificateRefreshReconciler) endTrustOverlap(ctx context.Context, config *operatorv1.CertManagerConfig,
+	ca *certmanagerv1.Certificate, secret *corev1.Secret, caStatus operatorv1.CARefreshStatus) (ctrl.Result, error) {
+	if caStatus.TrustOverlapUntil == nil {
+		return ctrl.Result{}, r.publishTrustBundle(ctx, ca, secret, false)
+	}
+	if wait := time.Until(caStatus.TrustOverlapUntil.Time); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, r.publishTrustBundle(ctx, ca, secret, true)
+	}
+
+	if err := r.publishTrustBundle(ctx, ca, secret, false); err != nil {
+		return ctrl.Result{}, err
+	}
+	caStatus.TrustOverlapUntil = nil
+	if err := r.updateCARefreshStatus(ctx, caStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.updateEvent(config, fmt.Sprintf("Dropped the previous CA from the trust bundle of CA %s/%s", ca.Namespace, ca.Name),
+		corev1.EventTypeNormal, "TrustOverlapEnded")
+	return ctrl.Result{}, nil
+}
+
+// publishTrustBundle writes the CA certificate of the secret to the trust
+// bundle ConfigMap of the CA. With keepPrevious, the unexpired certificates
+// already in the bundle stay in it after the current one.
+func (r *CertificateRefreshReconciler) publishTrustBundle(ctx context.Context, ca *certmanagerv1.Certificate,
+	secret *corev1.Secret, keepPrevious bool) error {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return fmt.Errorf("no PEM certificate in %s", corev1.TLSCertKey)
+	}
+	current := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes})
+
+	name := ca.Name + res.TrustBundleSuffix
+	cm := &corev1.ConfigMap{}
+	err := r.Reader.Get(ctx, types.NamespacedName{Namespace: ca.Namespace, Name: name}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	bundle := current
+	if keepPrevious && exists {
+		bundle = appendPrevious(bundle, block.Bytes, []byte(cm.Data[res.TrustBundleKey]))
+	}
+
+	if !exists {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ca.Namespace},
+			Data:       map[string]string{res.TrustBundleKey: string(bundle)},
+		}
+		// an imported CA has no certificate to own its bundle
+		if ca.UID != "" {
+			if err := controllerutil.SetControllerReference(ca, cm, r.Scheme); err != nil {
+				return err
+			}
+		}
+		logd.Info("Creating trust bundle", "Namespace", ca.Namespace, "Name", name)
+		return r.Client.Create(ctx, cm)
+	}
+	if cm.Data[res.TrustBundleKey] == string(bundle) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[res.TrustBundleKey] = string(bundle)
+	logd.Info("Updating trust bundle", "Namespace", ca.Namespace, "Name", name)
+	return r.Client.Update(ctx, cm)
+}
+
+// appendPrevious appends to the bundle the certificates of the previous
+// bundle other than the current one, skipping expired certificates
+func appendPrevious(bundle, currentDER, previous []byte) []byte {
+	now := time.Now()
+	for {
+		var block *pem.Block
+		block, previous = pem.Decode(previous)
+		if block == nil {
+			return bundle
+		}
+		if block.Type != "CERTIFICATE" || bytes.Equal(block.Bytes, currentDER) {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(cert.NotAfter) {
+			continue
+		}
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes})...)
+	}
+}
@@ -0,0 +1,155 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package certificaterefresh
+
+import (
+	"context"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestTrustOverlapDuringRotation(t *testing.T) {
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			RefreshCertsBasedOnCA: []operatorv1.CACertificate{{CertName: "app-ca", Namespace: testNS}},
+			CATrustBundle:         &operatorv1.CATrustBundleSpec{Enabled: true, OverlapDuration: &metav1.Duration{Duration: time.Hour}},
+		},
+	}
+	ca := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-ca", Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: "app-ca-secret", IsCA: true},
+	}
+	oldCA, newCA := selfSignedPEM(t, "old"), selfSignedPEM(t, "new")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-ca-secret", Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: oldCA},
+	}
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-issuer", Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: "app-ca-secret"},
+		}},
+	}
+	leaf := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: testNS},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: "leaf-tls",
+			IssuerRef:  cmmeta.ObjectReference{Name: "app-issuer"},
+		},
+	}
+
+	r := newReconciler(t, config, ca, secret, issuer, leaf)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: "app-ca-secret"}}
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bundle := func() []string {
+		t.Helper()
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: "app-ca" + res.TrustBundleSuffix}, cm); err != nil {
+			t.Fatal(err)
+		}
+		var certs []string
+		rest := []byte(cm.Data[res.TrustBundleKey])
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return certs
+			}
+			certs = append(certs, string(pem.EncodeToMemory(block)))
+		}
+	}
+	// editStatus changes the refresh status as time passing would
+	editStatus := func(edit func(*operatorv1.CARefreshStatus)) {
+		t.Helper()
+		config := &operatorv1.CertManagerConfig{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+			t.Fatal(err)
+		}
+		edit(&config.Status.CARefresh[0])
+		if err := r.Client.Status().Update(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := metav1.NewTime(time.Now().Add(-time.Second))
+
+	reconcile()
+	if got := bundle(); len(got) != 1 || got[0] != string(oldCA) {
+		t.Fatalf("expected the bundle to hold the current CA only, got %d certificates", len(got))
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	secret.Data[corev1.TLSCertKey] = newCA
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bundle trusts both CAs before any leaf is refreshed
+	reconcile()
+	if got := bundle(); len(got) != 2 || got[0] != string(newCA) || got[1] != string(oldCA) {
+		t.Fatalf("expected the bundle to hold the new and the previous CA, got %d certificates", len(got))
+	}
+	assertIssuing(t, r, leaf, false)
+
+	editStatus(func(s *operatorv1.CARefreshStatus) { s.NextRefreshTime = &past })
+	reconcile()
+	assertIssuing(t, r, leaf, true)
+
+	got := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(leaf), got); err != nil {
+		t.Fatal(err)
+	}
+	revision := 1
+	got.Status.Revision = &revision
+	util.SetCertificateCondition(got, certmanagerv1.CertificateConditionIssuing, cmmeta.ConditionFalse, "Issued", "")
+	util.SetCertificateCondition(got, certmanagerv1.CertificateConditionReady, cmmeta.ConditionTrue, "Ready", "")
+	if err := r.Client.Status().Update(ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	reconcile()
+	if got := bundle(); len(got) != 2 {
+		t.Fatalf("expected the previous CA to stay during the overlap, got %d certificates", len(got))
+	}
+
+	// the previous CA is dropped when the window ends
+	editStatus(func(s *operatorv1.CARefreshStatus) { s.TrustOverlapUntil = &past })
+	reconcile()
+	if got := bundle(); len(got) != 1 || got[0] != string(newCA) {
+		t.Fatalf("expected the bundle to hold the new CA only, got %d certificates", len(got))
+	}
+}
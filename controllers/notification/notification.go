@@ -0,0 +1,199 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// The events notified about a certificate
+const (
+	ExpiringEvent = "Expiring"
+	NotReadyEvent = "NotReady"
+)
+
+// defaultExpiryThresholds are the days before expiry notified about when a
+// policy does not set its thresholds
+var defaultExpiryThresholds = []int{30, 7, 1}
+
+// payload is the JSON body POSTed to the webhook
+type payload struct {
+	Policy        string          `json:"policy"`
+	Event         string          `json:"event"`
+	Certificate   certificateInfo `json:"certificate"`
+	ThresholdDays int             `json:"thresholdDays,omitempty"`
+	NotAfter      *metav1.Time    `json:"notAfter,omitempty"`
+	NotReadySince *metav1.Time    `json:"notReadySince,omitempty"`
+	Reason        string          `json:"reason,omitempty"`
+	Message       string          `json:"message,omitempty"`
+	Time          metav1.Time     `json:"time"`
+}
+
+type certificateInfo struct {
+	Namespace  string                 `json:"namespace"`
+	Name       string                 `json:"name"`
+	SecretName string                 `json:"secretName"`
+	CommonName string                 `json:"commonName,omitempty"`
+	DNSNames   []string               `json:"dnsNames,omitempty"`
+	IssuerRef  cmmeta.ObjectReference `json:"issuerRef"`
+}
+
+// notification is a notification due for a certificate. Its key stays the
+// same as long as the reason it is raised for does, so it is sent once.
+type notification struct {
+	key     string
+	payload payload
+}
+
+// dueNotifications returns the notifications the policy raises for the
+// certificate at the time
+func dueNotifications(policy *operatorv1.CertificateNotificationPolicy, cert *certmanagerv1.Certificate, now time.Time) []notification {
+	info := certificateInfo{
+		Namespace:  cert.Namespace,
+		Name:       cert.Name,
+		SecretName: cert.Spec.SecretName,
+		CommonName: cert.Spec.CommonName,
+		DNSNames:   cert.Spec.DNSNames,
+		IssuerRef:  cert.Spec.IssuerRef,
+	}
+	var due []notification
+
+	// only the closest threshold crossed is notified, the farther ones are
+	// no longer relevant
+	if notAfter := cert.Status.NotAfter; notAfter != nil {
+		thresholds := policy.Spec.ExpiryThresholds
+		if len(thresholds) == 0 {
+			thresholds = defaultExpiryThresholds
+		}
+		thresholds = append([]int{}, thresholds...)
+		sort.Ints(thresholds)
+		remaining := notAfter.Sub(now)
+		for _, days := range thresholds {
+			if remaining > time.Duration(days)*24*time.Hour {
+				continue
+			}
+			due = append(due, notification{
+				key: fmt.Sprintf("%s/%s/%s/%dd/%d", cert.Namespace, cert.Name, ExpiringEvent, days, notAfter.Unix()),
+				payload: payload{
+					Event:         ExpiringEvent,
+					Certificate:   info,
+					ThresholdDays: days,
+					NotAfter:      notAfter,
+					Message:       fmt.Sprintf("The certificate expires in %s", remaining.Round(time.Minute)),
+				},
+			})
+			break
+		}
+	}
+
+	if policy.Spec.NotReadyFor != nil {
+		ready := util.GetCertificateCondition(cert, certmanagerv1.CertificateConditionReady)
+		if ready != nil && ready.Status == cmmeta.ConditionFalse && ready.LastTransitionTime != nil &&
+			now.Sub(ready.LastTransitionTime.Time) >= policy.Spec.NotReadyFor.Duration {
+			due = append(due, notification{
+				key: fmt.Sprintf("%s/%s/%s/%d", cert.Namespace, cert.Name, NotReadyEvent, ready.LastTransitionTime.Unix()),
+				payload: payload{
+					Event:         NotReadyEvent,
+					Certificate:   info,
+					NotReadySince: ready.LastTransitionTime,
+					Reason:        ready.Reason,
+					Message:       ready.Message,
+				},
+			})
+		}
+	}
+	return due
+}
+
+// selectsIssuer returns whether the certificate is issued by the issuer
+// referenced, with the defaults of cert-manager for the kind and group
+func selectsIssuer(ref *cmmeta.ObjectReference, cert *certmanagerv1.Certificate) bool {
+	if ref == nil {
+		return true
+	}
+	return ref.Name == cert.Spec.IssuerRef.Name &&
+		defaulted(ref.Kind, certmanagerv1.IssuerKind) == defaulted(cert.Spec.IssuerRef.Kind, certmanagerv1.IssuerKind) &&
+		defaulted(ref.Group, "cert-manager.io") == defaulted(cert.Spec.IssuerRef.Group, "cert-manager.io")
+}
+
+func defaulted(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// retryBackoff is how long to wait before the next delivery of a notification
+// after failed attempts
+func retryBackoff(attempts int32) time.Duration {
+	backoff := 30 * time.Second
+	for i := int32(1); i < attempts && backoff < time.Hour; i++ {
+		backoff *= 2
+	}
+	if backoff > time.Hour {
+		backoff = time.Hour
+	}
+	return backoff
+}
+
+// sign returns the signature of the body sent in the signature header
+func sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver POSTs the body to the webhook. Only a 2xx response is a delivery.
+func deliver(ctx context.Context, httpClient *http.Client, url, key string, body, hmacKey []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(res.NotificationIDHeader, key)
+	if len(hmacKey) > 0 {
+		req.Header.Set(res.NotificationSignatureHeader, sign(hmacKey, body))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+)
+
+var logd = log.Log.WithName("controller_notification")
+
+// DeliveredCondition is the condition of a CertificateNotificationPolicy
+// reporting whether its notifications are delivered
+const DeliveredCondition = "Delivered"
+
+// resyncInterval is how often a policy is reconciled without any event, as
+// the expiry thresholds are crossed with time only
+const resyncInterval = 10 * time.Minute
+
+// deliveryTimeout bounds a request to the webhook of a policy
+const deliveryTimeout = 10 * time.Second
+
+// maxDeliveriesPerRound bounds the requests sent by a reconcile, so that a
+// slow webhook does not hold the worker. The other notifications are sent
+// by the next reconcile, right after.
+const maxDeliveriesPerRound = 20
+
+// maxNotificationRecords bounds the records kept in the status of a policy.
+// The notifications due beyond it are sent once the records of the first
+// ones are dropped, when they are no longer due.
+const maxNotificationRecords = 200
+
+// CertificateNotificationReconciler POSTs the notifications of each
+// CertificateNotificationPolicy to its webhook, keeping in the status of the
+// policy which ones were delivered and when to retry the others
+type CertificateNotificationReconciler struct {
+	Client     client.Client
+	Reader     client.Reader
+	Recorder   record.EventRecorder
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certificatenotificationpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certificatenotificationpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile sends the notifications due for the certificates selected by the
+// policy that were not delivered yet, and records the outcome
+func (r *CertificateNotificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Name", req.Name)
+
+	policy := &operatorv1.CertificateNotificationPolicy{}
+	if err := r.Client.Get(ctx, req.NamespacedName, policy); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	hmacKey, err := r.hmacKey(ctx, policy)
+	if err != nil {
+		reqLogger.Info("HMAC secret of the policy is not valid", "reason", err.Error())
+		r.Recorder.Event(policy, corev1.EventTypeWarning, "InvalidSecret", err.Error())
+		return ctrl.Result{RequeueAfter: resyncInterval}, r.updateStatus(ctx, policy, policy.Status, metav1.ConditionFalse, "InvalidSecret", err.Error())
+	}
+
+	certs, err := r.selectCertificates(ctx, policy)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := r.currentTime()
+	previous := map[string]operatorv1.NotificationRecord{}
+	for _, record := range policy.Status.Notifications {
+		previous[record.Key] = record
+	}
+	var due []notification
+	for i := range certs {
+		due = append(due, dueNotifications(policy, &certs[i], now)...)
+	}
+	// the notifications already recorded come first, so that they keep their
+	// records when more are due than the status holds
+	sort.Slice(due, func(i, j int) bool {
+		_, iRecorded := previous[due[i].key]
+		_, jRecorded := previous[due[j].key]
+		if iRecorded != jRecorded {
+			return iRecorded
+		}
+		return due[i].key < due[j].key
+	})
+
+	status := operatorv1.CertificateNotificationPolicyStatus{}
+	requeueAfter := resyncInterval
+	var lastError string
+	failed := false
+	// the deliveries back off as a whole while the webhook fails, rather
+	// than each notification trying it in turn
+	retryTime, failures := policyBackoff(policy.Status.Notifications)
+	halted := retryTime != nil && now.Before(retryTime.Time)
+	if halted {
+		requeueAfter = retryTime.Sub(now)
+	}
+	deliveries := 0
+	for i, n := range due {
+		if i == maxNotificationRecords {
+			status.Pending += len(due) - i
+			reqLogger.Info("Too many notifications due, deferring the others", "deferred", len(due)-i)
+			break
+		}
+		record, ok := previous[n.key]
+		if !ok {
+			record = operatorv1.NotificationRecord{
+				Key:         n.key,
+				Event:       n.payload.Event,
+				Certificate: n.payload.Certificate.Namespace + "/" + n.payload.Certificate.Name,
+			}
+		}
+		if record.DeliveredTime == nil && !halted && deliveries == maxDeliveriesPerRound {
+			halted = true
+			requeueAfter = 0
+		}
+		if record.DeliveredTime == nil && !halted {
+			deliveries++
+			if err := r.send(ctx, policy, n, hmacKey, now); err != nil {
+				record.Attempts++
+				if record.Attempts > failures {
+					failures = record.Attempts
+				}
+				wait := retryBackoff(failures)
+				record.NextAttemptTime = &metav1.Time{Time: now.Add(wait)}
+				record.LastError = err.Error()
+				failed = true
+				// the other notifications wait for the retry
+				halted = true
+				requeueAfter = wait
+				reqLogger.Info("Failed to deliver notification", "key", n.key, "attempts", record.Attempts, "reason", err.Error())
+			} else {
+				record.DeliveredTime = &metav1.Time{Time: now}
+				record.NextAttemptTime = nil
+				record.LastError = ""
+				reqLogger.Info("Delivered notification", "key", n.key)
+			}
+		}
+		if record.DeliveredTime == nil {
+			status.Pending++
+			if record.LastError != "" {
+				lastError = record.LastError
+			}
+		}
+		// the records of notifications no longer due are dropped
+		status.Notifications = append(status.Notifications, record)
+	}
+
+	if failed {
+		r.Recorder.Event(policy, corev1.EventTypeWarning, "DeliveryFailed", lastError)
+	}
+	result := ctrl.Result{RequeueAfter: requeueAfter}
+	if requeueAfter == 0 {
+		result = ctrl.Result{Requeue: true}
+	}
+	if lastError != "" {
+		return result, r.updateStatus(ctx, policy, status, metav1.ConditionFalse, "DeliveryFailed",
+			fmt.Sprintf("%d notifications not delivered: %s", status.Pending, lastError))
+	}
+	if status.Pending > 0 {
+		return result, r.updateStatus(ctx, policy, status, metav1.ConditionFalse, "DeliveryPending",
+			fmt.Sprintf("%d notifications not delivered yet", status.Pending))
+	}
+	return result, r.updateStatus(ctx, policy, status, metav1.ConditionTrue, "Delivered",
+		fmt.Sprintf("%d notifications delivered", len(status.Notifications)))
+}
+
+// policyBackoff returns when the deliveries of the policy are retried after
+// the webhook failed, and the failed deliveries the backoff is computed from
+func policyBackoff(records []operatorv1.NotificationRecord) (*metav1.Time, int32) {
+	var retryTime *metav1.Time
+	var failures int32
+	for i := range records {
+		record := &records[i]
+		if record.DeliveredTime != nil || record.NextAttemptTime == nil {
+			continue
+		}
+		if retryTime == nil || retryTime.Before(record.NextAttemptTime) {
+			retryTime = record.NextAttemptTime
+		}
+		if record.Attempts > failures {
+			failures = record.Attempts
+		}
+	}
+	return retryTime, failures
+}
+
+// send POSTs the notification to the webhook of the policy
+func (r *CertificateNotificationReconciler) send(ctx context.Context, policy *operatorv1.CertificateNotificationPolicy, n notification,
+	hmacKey []byte, now time.Time) error {
+	p := n.payload
+	p.Policy = policy.Name
+	p.Time = metav1.Time{Time: now}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: deliveryTimeout}
+	}
+	return deliver(ctx, httpClient, policy.Spec.Webhook.URL, n.key, body, hmacKey)
+}
+
+// hmacKey reads the key the payloads are signed with, if the policy has one
+func (r *CertificateNotificationReconciler) hmacKey(ctx context.Context, policy *operatorv1.CertificateNotificationPolicy) ([]byte, error) {
+	ref := policy.Spec.Webhook.HMACSecretRef
+	if ref == nil {
+		return nil, nil
+	}
+	// the secret is not labelled for the cache of the operator
+	secret := &corev1.Secret{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("secret %s/%s not found", ref.Namespace, ref.Name)
+		}
+		return nil, err
+	}
+	key, ok := secret.Data[ref.Key]
+	if !ok || len(key) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no key %s", ref.Namespace, ref.Name, ref.Key)
+	}
+	return key, nil
+}
+
+// selectCertificates returns the certificates matching the selector of the
+// policy
+func (r *CertificateNotificationReconciler) selectCertificates(ctx context.Context, policy *operatorv1.CertificateNotificationPolicy) ([]certmanagerv1.Certificate, error) {
+	selector := policy.Spec.Selector
+	opts := []client.ListOption{}
+	if selector.LabelSelector != nil {
+		certSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: certSelector})
+	}
+
+	var namespaces map[string]bool
+	if len(selector.Namespaces) > 0 {
+		namespaces = map[string]bool{}
+		for _, ns := range selector.Namespaces {
+			namespaces[ns] = true
+		}
+	}
+	if selector.NamespaceSelector != nil {
+		nsSelector, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		nsList := &corev1.NamespaceList{}
+		if err := r.Client.List(ctx, nsList, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+			return nil, err
+		}
+		matching := map[string]bool{}
+		for _, ns := range nsList.Items {
+			if namespaces == nil || namespaces[ns.Name] {
+				matching[ns.Name] = true
+			}
+		}
+		namespaces = matching
+	}
+
+	certList := &certmanagerv1.CertificateList{}
+	if err := r.Client.List(ctx, certList, opts...); err != nil {
+		return nil, err
+	}
+	var certs []certmanagerv1.Certificate
+	for _, cert := range certList.Items {
+		if namespaces != nil && !namespaces[cert.Namespace] {
+			continue
+		}
+		if !selectsIssuer(selector.IssuerRef, &cert) {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func (r *CertificateNotificationReconciler) currentTime() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+func (r *CertificateNotificationReconciler) updateStatus(ctx context.Context, policy *operatorv1.CertificateNotificationPolicy,
+	status operatorv1.CertificateNotificationPolicyStatus, conditionStatus metav1.ConditionStatus, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorv1.CertificateNotificationPolicy{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(policy), current); err != nil {
+			return err
+		}
+		status.Conditions = append([]metav1.Condition{}, current.Status.Conditions...)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               DeliveredCondition,
+			Status:             conditionStatus,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: current.Generation,
+		})
+		if reflect.DeepEqual(current.Status, status) {
+			return nil
+		}
+		current.Status = status
+		return r.Client.Status().Update(ctx, current)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CertificateNotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("certificatenotification-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertificateNotificationPolicy{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch the certificates being issued, renewed or failing. Deleted
+	// certificates are dropped from the status on the next resync.
+	return c.Watch(&source.Kind{Type: &certmanagerv1.Certificate{}}, handler.EnqueueRequestsFromMapFunc(r.allPolicies), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCert, okOld := e.ObjectOld.(*certmanagerv1.Certificate)
+			newCert, okNew := e.ObjectNew.(*certmanagerv1.Certificate)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldCert.Status, newCert.Status) ||
+				!labels.Equals(oldCert.Labels, newCert.Labels)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+}
+
+func (r *CertificateNotificationReconciler) allPolicies(obj client.Object) []reconcile.Request {
+	policyList := &operatorv1.CertificateNotificationPolicyList{}
+	if err := r.Client.List(context.TODO(), policyList); err != nil {
+		logd.Error(err, "Failed to list CertificateNotificationPolicies")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policyList.Items))
+	for _, policy := range policyList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}})
+	}
+	return requests
+}
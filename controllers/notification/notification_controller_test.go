@@ -0,0 +1,329 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// webhook records the notifications it receives, and fails them while
+// failing is set
+type webhook struct {
+	mu       sync.Mutex
+	failing  bool
+	requests int
+	received []payload
+	headers  []http.Header
+	bodies   [][]byte
+}
+
+func (w *webhook) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.requests++
+	if w.failing {
+		http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.received = append(w.received, p)
+	w.headers = append(w.headers, req.Header)
+	w.bodies = append(w.bodies, body)
+}
+
+func certificate(namespace, name string, notAfter time.Time, ready cmmeta.ConditionStatus, since time.Time) *certmanagerv1.Certificate {
+	return &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: name + "-secret",
+			DNSNames:   []string{name + ".example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "team-ca"},
+		},
+		Status: certmanagerv1.CertificateStatus{
+			NotAfter: &metav1.Time{Time: notAfter},
+			Conditions: []certmanagerv1.CertificateCondition{{
+				Type:               certmanagerv1.CertificateConditionReady,
+				Status:             ready,
+				Reason:             "Failed",
+				Message:            "issuance failed",
+				LastTransitionTime: &metav1.Time{Time: since},
+			}},
+		},
+	}
+}
+
+func newReconciler(t *testing.T, now *time.Time, objs ...client.Object) *CertificateNotificationReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, operatorv1.AddToScheme, certmanagerv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &CertificateNotificationReconciler{
+		Client:   c,
+		Reader:   c,
+		Recorder: record.NewFakeRecorder(100),
+		now:      func() time.Time { return *now },
+	}
+}
+
+func TestReconcileNotifiesOnce(t *testing.T) {
+	w := &webhook{}
+	server := httptest.NewServer(w)
+	defer server.Close()
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := &operatorv1.CertificateNotificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: operatorv1.CertificateNotificationPolicySpec{
+			Selector: operatorv1.CertificateSelector{
+				Namespaces: []string{"team"},
+				IssuerRef:  &cmmeta.ObjectReference{Name: "team-ca", Kind: "Issuer"},
+			},
+			ExpiryThresholds: []int{30, 7, 1},
+			NotReadyFor:      &metav1.Duration{Duration: time.Hour},
+			Webhook: operatorv1.NotificationWebhook{
+				URL:           server.URL,
+				HMACSecretRef: &operatorv1.SourceObjectKeySelector{Name: "hmac", Namespace: "team", Key: "key"},
+			},
+		},
+	}
+	hmacSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "hmac", Namespace: "team"},
+		Data:       map[string][]byte{"key": []byte("shared")},
+	}
+	expiring := certificate("team", "expiring", now.Add(5*24*time.Hour), cmmeta.ConditionTrue, now.Add(-time.Hour))
+	failing := certificate("team", "failing", now.Add(60*24*time.Hour), cmmeta.ConditionFalse, now.Add(-2*time.Hour))
+	recent := certificate("team", "recent", now.Add(60*24*time.Hour), cmmeta.ConditionFalse, now.Add(-time.Minute))
+	otherNS := certificate("other", "expiring", now.Add(time.Hour), cmmeta.ConditionTrue, now.Add(-time.Hour))
+	otherIssuer := certificate("team", "other-issuer", now.Add(time.Hour), cmmeta.ConditionTrue, now.Add(-time.Hour))
+	otherIssuer.Spec.IssuerRef.Name = "other-ca"
+	r := newReconciler(t, &now, policy, hmacSecret, expiring, failing, recent, otherNS, otherIssuer)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: policy.Name}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.received) != 2 {
+		t.Fatalf("got %d notifications, want 2: %+v", len(w.received), w.received)
+	}
+	if p := w.received[0]; p.Event != ExpiringEvent || p.Certificate.Name != "expiring" || p.ThresholdDays != 7 || p.Policy != "team" {
+		t.Errorf("unexpected expiry notification %+v", p)
+	}
+	if p := w.received[1]; p.Event != NotReadyEvent || p.Certificate.Name != "failing" || p.Reason != "Failed" {
+		t.Errorf("unexpected not ready notification %+v", p)
+	}
+	for i, h := range w.headers {
+		if h.Get(res.NotificationSignatureHeader) != sign([]byte("shared"), w.bodies[i]) {
+			t.Errorf("notification %d has signature %q", i, h.Get(res.NotificationSignatureHeader))
+		}
+		if h.Get(res.NotificationIDHeader) == "" {
+			t.Errorf("notification %d has no id", i)
+		}
+	}
+
+	// the notifications are not sent again
+	now = now.Add(30 * time.Minute)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.received) != 2 {
+		t.Fatalf("got %d notifications after a resync, want 2", len(w.received))
+	}
+
+	// crossing the next threshold notifies again, retrying until delivered,
+	// as does the certificate that has now been failing for long enough
+	w.failing = true
+	now = expiring.Status.NotAfter.Add(-12 * time.Hour)
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter != retryBackoff(1) {
+		t.Errorf("got requeue after %v, want %v", result.RequeueAfter, retryBackoff(1))
+	}
+	current := &operatorv1.CertificateNotificationPolicy{}
+	if err := r.Client.Get(ctx, req.NamespacedName, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Status.Pending != 2 || !meta.IsStatusConditionFalse(current.Status.Conditions, DeliveredCondition) {
+		t.Errorf("unexpected status after a failed delivery %+v", current.Status)
+	}
+
+	w.failing = false
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.received) != 2 {
+		t.Fatal("the delivery was retried before its backoff")
+	}
+	now = now.Add(retryBackoff(1))
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.received) != 4 || w.received[2].ThresholdDays != 1 || w.received[3].Certificate.Name != "recent" {
+		t.Fatalf("unexpected notifications after the retry %+v", w.received)
+	}
+	current = &operatorv1.CertificateNotificationPolicy{}
+	if err := r.Client.Get(ctx, req.NamespacedName, current); err != nil {
+		t.Fatal(err)
+	}
+	if current.Status.Pending != 0 || !meta.IsStatusConditionTrue(current.Status.Conditions, DeliveredCondition) {
+		t.Errorf("unexpected status after the retry %+v", current.Status)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	for attempts, want := range map[int32]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 20: time.Hour} {
+		if got := retryBackoff(attempts); got != want {
+			t.Errorf("attempt %d: got backoff %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func expiringPolicy(url string) *operatorv1.CertificateNotificationPolicy {
+	return &operatorv1.CertificateNotificationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: operatorv1.CertificateNotificationPolicySpec{
+			Selector:         operatorv1.CertificateSelector{Namespaces: []string{"team"}},
+			ExpiryThresholds: []int{7},
+			Webhook:          operatorv1.NotificationWebhook{URL: url},
+		},
+	}
+}
+
+func TestReconcileBacksOffPerPolicy(t *testing.T) {
+	w := &webhook{failing: true}
+	server := httptest.NewServer(w)
+	defer server.Close()
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	objs := []client.Object{expiringPolicy(server.URL)}
+	for i := 0; i < 3; i++ {
+		objs = append(objs, certificate("team", fmt.Sprintf("expiring-%d", i), now.Add(24*time.Hour), cmmeta.ConditionTrue, now.Add(-time.Hour)))
+	}
+	r := newReconciler(t, &now, objs...)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "team"}}
+
+	// the round stops at the first failure
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.requests != 1 || result.RequeueAfter != retryBackoff(1) {
+		t.Errorf("got %d requests and requeue after %v, want 1 and %v", w.requests, result.RequeueAfter, retryBackoff(1))
+	}
+
+	// no notification is tried before the backoff of the policy
+	now = now.Add(retryBackoff(1) / 2)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if w.requests != 1 {
+		t.Errorf("got %d requests during the backoff, want 1", w.requests)
+	}
+
+	// the backoff grows with the failures of the webhook
+	now = now.Add(retryBackoff(1))
+	if result, err = r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if w.requests != 2 || result.RequeueAfter != retryBackoff(2) {
+		t.Errorf("got %d requests and requeue after %v, want 2 and %v", w.requests, result.RequeueAfter, retryBackoff(2))
+	}
+
+	w.failing = false
+	now = now.Add(retryBackoff(2))
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.received) != 3 {
+		t.Errorf("got %d notifications once the webhook is back, want 3", len(w.received))
+	}
+}
+
+func TestReconcileBoundsNotificationRecords(t *testing.T) {
+	w := &webhook{}
+	server := httptest.NewServer(w)
+	defer server.Close()
+
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	objs := []client.Object{expiringPolicy(server.URL)}
+	for i := 0; i < maxNotificationRecords+5; i++ {
+		objs = append(objs, certificate("team", fmt.Sprintf("expiring-%03d", i), now.Add(24*time.Hour), cmmeta.ConditionTrue, now.Add(-time.Hour)))
+	}
+	r := newReconciler(t, &now, objs...)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "team"}}
+
+	// each round sends a bounded number of notifications
+	for rounds := 0; ; rounds++ {
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(w.received) > (rounds+1)*maxDeliveriesPerRound {
+			t.Fatalf("got %d notifications in %d rounds", len(w.received), rounds+1)
+		}
+		if !result.Requeue {
+			break
+		}
+	}
+	if len(w.received) != maxNotificationRecords {
+		t.Errorf("got %d notifications, want %d", len(w.received), maxNotificationRecords)
+	}
+	current := &operatorv1.CertificateNotificationPolicy{}
+	if err := r.Client.Get(ctx, req.NamespacedName, current); err != nil {
+		t.Fatal(err)
+	}
+	if len(current.Status.Notifications) != maxNotificationRecords || current.Status.Pending != 5 {
+		t.Errorf("got %d records and %d pending, want %d and 5", len(current.Status.Notifications), current.Status.Pending, maxNotificationRecords)
+	}
+}
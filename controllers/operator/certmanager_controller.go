@@ -19,14 +19,19 @@ package operator
 import (
 	"context"
 	"fmt"
+	"net"
 	"reflect"
+	"sync/atomic"
+	"time"
 
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
 	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
 	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
 	admRegv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -59,6 +64,16 @@ type CertManagerReconciler struct {
 	Scheme       *runtime.Scheme
 	Recorder     record.EventRecorder
 	NS           string
+	// ResyncPeriod is how often the CertManagerConfig is reconciled again,
+	// DefaultResyncPeriod when it is not set
+	ResyncPeriod time.Duration
+
+	// smokeCheckTime is when the last smoke check completed
+	smokeCheckTime time.Time
+	// reconciled is set once the CertManagerConfig is reconciled successfully
+	reconciled atomic.Bool
+	// dial connects to cert-manager-webhook for the readiness check
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
 }
 
 //+kubebuilder:rbac:groups=operator.ibm.com,resources=certmanagerconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -115,7 +130,10 @@ type CertManagerReconciler struct {
 
 //+kubebuilder:rbac:groups="authorization.k8s.io",resources=subjectaccessreviews,verbs=create
 
-//+kubebuilder:rbac:groups="ibmcpcs.ibm.com",resources=secretshares,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups="ibmcpcs.ibm.com",resources=secretshares,verbs=create;get;list;watch;update;delete
+//+kubebuilder:rbac:groups="operator.open-cluster-management.io",resources=multiclusterhubs,verbs=get;list
+
+//+kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -180,7 +198,49 @@ func (r *CertManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	r.updateEvent(instance, "Deployed cert-manager successfully", corev1.EventTypeNormal, "Deployed")
 	r.updateStatus(instance, "Successfully deployed cert-manager")
 
-	return ctrl.Result{}, nil
+	// Bootstrap the CS CA chain once cert-manager is up. While it is not ready,
+	// the watches on its issuers, certificate and the deployments bring the
+	// next reconcile.
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		logd.Error(err, "Error with bootstrapping the CS CA, requeueing")
+		r.updateEvent(instance, err.Error(), corev1.EventTypeWarning, "CSCAFailed")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.reconcileRevocationService(instance); err != nil {
+		logd.Error(err, "Error with the revocation service, requeueing")
+		r.updateEvent(instance, err.Error(), corev1.EventTypeWarning, "RevocationServiceFailed")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.reconcileMonitoring(instance); err != nil {
+		logd.Error(err, "Error with the monitoring of cert-manager, requeueing")
+		r.updateEvent(instance, err.Error(), corev1.EventTypeWarning, "MonitoringFailed")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Share the CS CA with RHACM when it is installed
+	if err := r.reconcileRhacm(instance); err != nil {
+		logd.Error(err, "Error with sharing the CS CA with RHACM, requeueing")
+		r.updateEvent(instance, err.Error(), corev1.EventTypeWarning, "RhacmIntegrationFailed")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Check cert-manager issues certificates, periodically
+	smokeCheckAfter, err := r.reconcileSmokeCheck(instance)
+	if err != nil {
+		logd.Error(err, "Error with the smoke check of cert-manager, requeueing")
+		r.updateEvent(instance, err.Error(), corev1.EventTypeWarning, "SmokeCheckError")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	lastSuccessfulReconcile.SetToCurrentTime()
+	r.reconciled.Store(true)
+	requeueAfter := r.resyncPeriod()
+	if smokeCheckAfter > 0 && smokeCheckAfter < requeueAfter {
+		requeueAfter = smokeCheckAfter
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *CertManagerReconciler) updateEvent(instance *operatorv1.CertManagerConfig, message, event, reason string) {
@@ -197,10 +257,18 @@ func (r *CertManagerReconciler) updateStatus(instance *operatorv1.CertManagerCon
 }
 
 func (r *CertManagerReconciler) PreReqs(instance *operatorv1.CertManagerConfig) error {
-	if err := checkRbac(instance, r.Scheme, r.Client, r.NS); err != nil {
+	defer observePhase("prereqs", time.Now())
+	rbacStart := time.Now()
+	err := checkRbac(instance, r.Scheme, r.Client, r.NS)
+	observePhase("rbac", rbacStart)
+	if err != nil {
 		logd.V(2).Info("Checking RBAC failed")
 		return err
 	}
+	if err := protectCRDs(r.Client); err != nil {
+		logd.V(2).Info("Protecting cert-manager CRDs failed")
+		return err
+	}
 	return nil
 }
 
@@ -215,7 +283,13 @@ func (r *CertManagerReconciler) deployments(instance *operatorv1.CertManagerConf
 
 	if instance.Spec.Webhook {
 		// Check webhook prerequisites
-		if err := webhookPrereqs(instance, r.Scheme, r.Client, r.NS); err != nil {
+		webhookStart := time.Now()
+		err := webhookPrereqs(instance, r.Scheme, r.Client, r.NS)
+		observePhase("webhook", webhookStart)
+		if err != nil {
+			return err
+		}
+		if err := r.labelServingSecret(); err != nil {
 			return err
 		}
 		// Deploy webhook and cainjector
@@ -414,5 +488,26 @@ func (r *CertManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err != nil {
 		return err
 	}
+	// Watch the issuers and certificate of the CS CA chain - in case of deletion or drift
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.Issuer{}}, handler.EnqueueRequestsFromMapFunc(r.csCAObject))
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.Certificate{}}, handler.EnqueueRequestsFromMapFunc(r.csCAObject))
+	if err != nil {
+		return err
+	}
+	// Watch the secret the CS CA is imported from, and the CS CA secret it is imported into
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.csCAImportSecret))
+	if err != nil {
+		return err
+	}
+	// Watch the objects created without being watched as owned - in case of deletion or changes
+	for _, kind := range []client.Object{&rbacv1.Role{}, &rbacv1.RoleBinding{}, &apiextensionv1.CustomResourceDefinition{}, &corev1.Secret{}} {
+		err = c.Watch(&source.Kind{Type: kind}, handler.EnqueueRequestsFromMapFunc(r.operandObject), operandObjectPredicate)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
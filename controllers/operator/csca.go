@@ -0,0 +1,393 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// csCAEnabled returns true unless the CS CA chain is turned off in the
+// CertManagerConfig
+func csCAEnabled(instance *operatorv1.CertManagerConfig) bool {
+	return instance.Spec.CSCA == nil || !instance.Spec.CSCA.Disabled
+}
+
+// reconcileCSCA creates the CS CA chain once the cert-manager operands are
+// ready and puts its issuers and certificate back in shape when they drift.
+// It returns false while the chain waits for cert-manager or for its issuers
+// and certificate to become ready.
+//
+// The chain is not owned by the CertManagerConfig: deleting it must not
+// delete the CA every certificate of the platform chains to. Objects of the
+// chain the operator did not create are left to whoever created them.
+func (r *CertManagerReconciler) reconcileCSCA(instance *operatorv1.CertManagerConfig) (bool, error) {
+	if !csCAEnabled(instance) {
+		return true, r.updateCSCAStatus(instance, nil)
+	}
+
+	if ready, message, err := r.operandsReady(instance); err != nil {
+		return false, err
+	} else if !ready {
+		logd.V(2).Info("Waiting for cert-manager before bootstrapping the CS CA", "reason", message)
+		return false, r.updateCSCAStatus(instance, &operatorv1.CSCAStatus{Message: message})
+	}
+	if spec := instance.Spec.CSCA; spec != nil && spec.Import != nil {
+		return r.importCSCA(instance, spec.Import)
+	}
+
+	unmanaged, err := r.unmanagedCSCAObjects()
+	if err != nil {
+		return false, err
+	}
+	if len(unmanaged) > 0 {
+		return r.reportUnmanagedCSCA(instance, unmanaged)
+	}
+
+	selfSigned := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASelfSignedIssuerName, Namespace: r.NS, Labels: res.CSCAIssuerLabelMap,
+			Annotations: res.CSCAManagedAnnotationMap},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			SelfSigned: &certmanagerv1.SelfSignedIssuer{},
+		}},
+	}
+	if err := r.applyIssuer(selfSigned); err != nil {
+		return false, err
+	}
+	if err := r.applyCertificate(csCACertificate(instance, r.NS)); err != nil {
+		return false, err
+	}
+	if err := r.applyIssuer(csCAIssuer(instance, r.NS)); err != nil {
+		return false, err
+	}
+
+	status, err := r.csCAStatus()
+	if err != nil {
+		return false, err
+	}
+	return status.Ready, r.updateCSCAStatus(instance, status)
+}
+
+// unmanagedCSCAObjects returns the objects of the CS CA chain that exist and
+// were not created by the operator. The secret of the CA is one of them when
+// it exists without the certificate, as cert-manager would overwrite it,
+// unless the operator created it to import a CA.
+func (r *CertManagerReconciler) unmanagedCSCAObjects() ([]string, error) {
+	var unmanaged []string
+	objects := []struct {
+		kind string
+		obj  client.Object
+		name string
+	}{
+		{certmanagerv1.IssuerKind, &certmanagerv1.Issuer{}, res.CSCASelfSignedIssuerName},
+		{certmanagerv1.CertificateKind, &certmanagerv1.Certificate{}, res.CSCACertName},
+		{certmanagerv1.IssuerKind, &certmanagerv1.Issuer{}, res.CSCAIssuerName},
+	}
+	certificateExists := false
+	for _, o := range objects {
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: o.name}, o.obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if o.name == res.CSCACertName {
+			certificateExists = true
+		}
+		if _, ok := o.obj.GetAnnotations()[res.CSCAManagedAnnotation]; !ok {
+			unmanaged = append(unmanaged, o.kind+" "+o.name)
+		}
+	}
+	if !certificateExists {
+		// the CA secret is not labelled for the cache of the operator
+		secret := &corev1.Secret{}
+		err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCASecretName}, secret)
+		if err == nil {
+			if _, ok := secret.Annotations[res.CSCAManagedAnnotation]; !ok {
+				unmanaged = append(unmanaged, "Secret "+res.CSCASecretName)
+			}
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return unmanaged, nil
+}
+
+// reportUnmanagedCSCA reports the CS CA chain created by another component,
+// ready when all of its objects are
+func (r *CertManagerReconciler) reportUnmanagedCSCA(instance *operatorv1.CertManagerConfig, unmanaged []string) (bool, error) {
+	message := fmt.Sprintf("Leaving the CS CA chain alone, %s not created by the operator", strings.Join(unmanaged, ", "))
+	if instance.Status.CSCA == nil || instance.Status.CSCA.Message != message {
+		logd.Info("Leaving the CS CA chain alone", "unmanaged", unmanaged)
+		r.updateEvent(instance, message, corev1.EventTypeWarning, "CSCANotManaged")
+	}
+	status, err := r.csCAStatus()
+	if errors.IsNotFound(err) {
+		status = &operatorv1.CSCAStatus{}
+	} else if err != nil {
+		return false, err
+	}
+	status.Message = message
+	return status.Ready, r.updateCSCAStatus(instance, status)
+}
+
+// operandsReady returns whether the cert-manager deployments the CS CA chain
+// depends on have available replicas
+func (r *CertManagerReconciler) operandsReady(instance *operatorv1.CertManagerConfig) (bool, string, error) {
+	names := []string{res.CertManagerControllerName}
+	if instance.Spec.Webhook {
+		names = append(names, res.CertManagerWebhookName, res.CertManagerCainjectorName)
+	}
+	for _, name := range names {
+		deploy := &appsv1.Deployment{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: name}, deploy); err != nil {
+			if errors.IsNotFound(err) {
+				return false, fmt.Sprintf("Waiting for deployment %s to be created", name), nil
+			}
+			return false, "", err
+		}
+		if deploy.Status.AvailableReplicas == 0 {
+			return false, fmt.Sprintf("Waiting for deployment %s to be available", name), nil
+		}
+	}
+	return true, "", nil
+}
+
+// csCACertificate returns the CS CA certificate configured in the
+// CertManagerConfig
+func csCACertificate(instance *operatorv1.CertManagerConfig, namespace string) *certmanagerv1.Certificate {
+	algorithm := res.DefaultCSCAKeyAlgorithm
+	size := 0
+	duration, renewBefore := res.DefaultCSCADuration, res.DefaultCSCARenewBefore
+	if spec := instance.Spec.CSCA; spec != nil {
+		if spec.KeyAlgorithm != "" {
+			algorithm = spec.KeyAlgorithm
+		}
+		size = spec.KeySize
+		if spec.Duration != nil {
+			duration = spec.Duration.Duration
+		}
+		if spec.RenewBefore != nil {
+			renewBefore = spec.RenewBefore.Duration
+		}
+	}
+
+	// signing the CRL of cs-ca-issuer takes the crl sign usage, which
+	// cert-manager does not set on CA certificates by default. It is set
+	// whether or not the CRLs are enabled: a change of the usages re-issues
+	// the CS CA, and with it every certificate signed by cs-ca-issuer
+	usages := []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageCertSign, certmanagerv1.UsageCRLSign}
+
+	return &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: namespace, Labels: res.CSCAIssuerLabelMap,
+			Annotations: res.CSCAManagedAnnotationMap},
+		Spec: certmanagerv1.CertificateSpec{
+			CommonName: res.CSCACertName,
+			IsCA:       true,
+			SecretName: res.CSCASecretName,
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  res.CSCASelfSignedIssuerName,
+				Kind:  certmanagerv1.IssuerKind,
+				Group: certmanagerv1.SchemeGroupVersion.Group,
+			},
+			PrivateKey: &certmanagerv1.CertificatePrivateKey{
+				Algorithm: certmanagerv1.PrivateKeyAlgorithm(algorithm),
+				Size:      size,
+			},
+			Duration:    &metav1.Duration{Duration: duration},
+			RenewBefore: &metav1.Duration{Duration: renewBefore},
+			Usages:      usages,
+		},
+	}
+}
+
+// csCAIssuer returns the cs-ca-issuer CA Issuer, listing its CRL in the
+// certificates it issues when the CRLs are enabled
+func csCAIssuer(instance *operatorv1.CertManagerConfig, namespace string) *certmanagerv1.Issuer {
+	ca := &certmanagerv1.CAIssuer{SecretName: res.CSCASecretName}
+	if crlEnabled(instance) {
+		ca.CRLDistributionPoints = []string{crlURL(namespace, res.CSCAIssuerName)}
+	}
+	if ocspAnswersFor(instance, res.CSCAIssuerName) {
+		ca.OCSPServers = []string{ocspURL(namespace)}
+	}
+	return &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: namespace, Labels: res.CSCAIssuerLabelMap,
+			Annotations: res.CSCAManagedAnnotationMap},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: ca,
+		}},
+	}
+}
+
+// applyIssuer creates the issuer, or updates its labels and configuration
+// when they were changed
+func (r *CertManagerReconciler) applyIssuer(issuer *certmanagerv1.Issuer) error {
+	existing := &certmanagerv1.Issuer{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: issuer.Namespace, Name: issuer.Name}, existing)
+	if errors.IsNotFound(err) {
+		logd.Info("Creating issuer", "Namespace", issuer.Namespace, "Name", issuer.Name)
+		return r.Client.Create(context.TODO(), issuer)
+	} else if err != nil {
+		return err
+	}
+
+	old := existing.DeepCopy()
+	existing.Labels = mergeLabels(existing.Labels, issuer.Labels)
+	existing.Spec.IssuerConfig = issuer.Spec.IssuerConfig
+	if equality.Semantic.DeepEqual(old, existing) {
+		return nil
+	}
+	logd.Info("Updating issuer that drifted", "Namespace", issuer.Namespace, "Name", issuer.Name)
+	if err := r.Client.Update(context.TODO(), existing); err != nil {
+		return err
+	}
+	recordUpdate("Issuer", metadataDrift(existing.ObjectMeta, old.ObjectMeta))
+	return nil
+}
+
+// applyCertificate creates the certificate, or updates its labels and the
+// fields of its spec set by the operator when they were changed
+func (r *CertManagerReconciler) applyCertificate(crt *certmanagerv1.Certificate) error {
+	existing := &certmanagerv1.Certificate{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: crt.Namespace, Name: crt.Name}, existing)
+	if errors.IsNotFound(err) {
+		logd.Info("Creating certificate", "Namespace", crt.Namespace, "Name", crt.Name)
+		return r.Client.Create(context.TODO(), crt)
+	} else if err != nil {
+		return err
+	}
+
+	old := existing.DeepCopy()
+	existing.Labels = mergeLabels(existing.Labels, crt.Labels)
+	existing.Spec.CommonName = crt.Spec.CommonName
+	existing.Spec.IsCA = crt.Spec.IsCA
+	existing.Spec.SecretName = crt.Spec.SecretName
+	existing.Spec.IssuerRef = crt.Spec.IssuerRef
+	existing.Spec.PrivateKey = crt.Spec.PrivateKey
+	existing.Spec.Duration = crt.Spec.Duration
+	existing.Spec.RenewBefore = crt.Spec.RenewBefore
+	existing.Spec.Usages = crt.Spec.Usages
+	if equality.Semantic.DeepEqual(old, existing) {
+		return nil
+	}
+	logd.Info("Updating certificate that drifted", "Namespace", crt.Namespace, "Name", crt.Name)
+	if err := r.Client.Update(context.TODO(), existing); err != nil {
+		return err
+	}
+	recordUpdate("Certificate", metadataDrift(existing.ObjectMeta, old.ObjectMeta))
+	return nil
+}
+
+// csCAStatus reads the state of the CS CA chain from cert-manager
+func (r *CertManagerReconciler) csCAStatus() (*operatorv1.CSCAStatus, error) {
+	status := &operatorv1.CSCAStatus{}
+
+	for _, name := range []string{res.CSCASelfSignedIssuerName, res.CSCAIssuerName} {
+		issuer := &certmanagerv1.Issuer{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: name}, issuer); err != nil {
+			return nil, err
+		}
+		if !util.IsIssuerReady(issuer) && status.Message == "" {
+			status.Message = fmt.Sprintf("Waiting for issuer %s to be ready", name)
+		}
+	}
+
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCACertName}, crt); err != nil {
+		return nil, err
+	}
+	status.NotAfter = crt.Status.NotAfter
+	status.RenewalTime = crt.Status.RenewalTime
+	if !util.IsCertificateReady(crt) {
+		status.Message = fmt.Sprintf("Waiting for certificate %s to be ready", res.CSCACertName)
+	}
+	status.Ready = status.Message == ""
+	return status, nil
+}
+
+func (r *CertManagerReconciler) updateCSCAStatus(instance *operatorv1.CertManagerConfig, status *operatorv1.CSCAStatus) error {
+	if reflect.DeepEqual(instance.Status.CSCA, status) {
+		return nil
+	}
+	instance.Status.CSCA = status
+	return r.Client.Status().Update(context.TODO(), instance)
+}
+
+// mergeLabels returns the existing labels with the wanted ones set
+func mergeLabels(existing, wanted map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range wanted {
+		merged[k] = v
+	}
+	return merged
+}
+
+// csCAObject maps the objects of the CS CA chain, and the test certificate
+// of the smoke check, to the CertManagerConfig
+func (r *CertManagerReconciler) csCAObject(obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.NS {
+		return nil
+	}
+	switch obj.GetName() {
+	case res.CSCASelfSignedIssuerName, res.CSCAIssuerName, res.CSCACertName, res.SmokeCheckCertName:
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}}
+	}
+	return nil
+}
+
+// csCAImportSecret maps the CS CA secret and the secret the CS CA is
+// imported from to the CertManagerConfig
+func (r *CertManagerReconciler) csCAImportSecret(obj client.Object) []reconcile.Request {
+	instance := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: res.CertManagerInstanceName}, instance); err != nil {
+		return nil
+	}
+	if instance.Spec.CSCA == nil || instance.Spec.CSCA.Import == nil {
+		return nil
+	}
+	imp := instance.Spec.CSCA.Import
+	namespace := imp.Namespace
+	if namespace == "" {
+		namespace = r.NS
+	}
+	if (obj.GetNamespace() == namespace && obj.GetName() == imp.SecretName) ||
+		(obj.GetNamespace() == r.NS && obj.GetName() == res.CSCASecretName) {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}}
+	}
+	return nil
+}
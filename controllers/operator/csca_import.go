@@ -0,0 +1,310 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// importCSCA swaps the CA keypair of the import secret into the CS CA secret
+// once it is validated, and stops managing the self-signed CS CA certificate.
+// The leaf certificates are refreshed by the certificate refresh controller
+// when it sees the new CA in the secret. The import is rejected when an
+// object of the CS CA chain was not created by the operator.
+func (r *CertManagerReconciler) importCSCA(instance *operatorv1.CertManagerConfig, imp *operatorv1.CSCAImport) (bool, error) {
+	namespace := imp.Namespace
+	if namespace == "" {
+		namespace = r.NS
+	}
+	minValidity := res.DefaultCSCAImportMinValidity
+	if imp.MinValidity != nil {
+		minValidity = imp.MinValidity.Duration
+	}
+
+	source := &corev1.Secret{}
+	if err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: imp.SecretName}, source); err != nil {
+		if errors.IsNotFound(err) {
+			return true, r.rejectImport(instance, fmt.Sprintf("secret %s/%s not found", namespace, imp.SecretName))
+		}
+		return false, err
+	}
+	if _, ok := source.Labels[res.SecretWatchLabel]; !ok {
+		// label the secret to get its updates, e.g. when the CA is renewed
+		if source.Labels == nil {
+			source.Labels = map[string]string{}
+		}
+		source.Labels[res.SecretWatchLabel] = ""
+		if err := r.Client.Update(context.TODO(), source); err != nil {
+			return false, err
+		}
+	}
+
+	ca, rootPEM, err := validateImportedCA(source, minValidity)
+	if err != nil {
+		return true, r.rejectImport(instance, fmt.Sprintf("secret %s/%s: %v", namespace, imp.SecretName, err))
+	}
+
+	unmanaged, err := r.unmanagedCSCAObjects()
+	if err != nil {
+		return false, err
+	}
+	if len(unmanaged) > 0 {
+		return true, r.rejectImport(instance, fmt.Sprintf("%s not created by the operator", strings.Join(unmanaged, ", ")))
+	}
+
+	// cert-manager would overwrite the imported keypair with a self-signed one,
+	// so the secret is swapped only once the certificate is gone
+	crt := &certmanagerv1.Certificate{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCACertName}, crt)
+	if err == nil {
+		if crt.DeletionTimestamp.IsZero() {
+			// the secret of the certificate is the operator's too, it must not
+			// be taken for a foreign one once the certificate is gone
+			if err := r.markCSCASecretManaged(); err != nil {
+				return false, err
+			}
+			logd.Info("Deleting the self-signed CS CA certificate replaced by the imported CA")
+			if err := r.Client.Delete(context.TODO(), crt); err != nil && !errors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		err = r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCACertName}, &certmanagerv1.Certificate{})
+	}
+	if err == nil {
+		message := fmt.Sprintf("Waiting for certificate %s to be deleted before importing the CA", res.CSCACertName)
+		return false, r.updateCSCAStatus(instance, &operatorv1.CSCAStatus{Message: message})
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	swapped, err := r.swapCSCASecret(source, rootPEM)
+	if err != nil {
+		return false, err
+	}
+	if swapped {
+		r.updateEvent(instance, fmt.Sprintf("Imported CA %s from secret %s/%s into %s", ca.Subject.String(), namespace, imp.SecretName, res.CSCASecretName),
+			corev1.EventTypeNormal, "CSCAImported")
+	}
+
+	if err := r.applyIssuer(csCAIssuer(instance, r.NS)); err != nil {
+		return false, err
+	}
+	issuer := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCAIssuerName}, issuer); err != nil {
+		return false, err
+	}
+
+	notAfter := metav1.NewTime(ca.NotAfter)
+	status := &operatorv1.CSCAStatus{Ready: true, NotAfter: &notAfter, Imported: true}
+	if !util.IsIssuerReady(issuer) {
+		status.Ready = false
+		status.Message = fmt.Sprintf("Waiting for issuer %s to be ready", res.CSCAIssuerName)
+	}
+	return status.Ready, r.updateCSCAStatus(instance, status)
+}
+
+// rejectImport reports why the CA could not be imported. The CS CA secret is
+// left as it is.
+func (r *CertManagerReconciler) rejectImport(instance *operatorv1.CertManagerConfig, reason string) error {
+	message := "Cannot import CA: " + reason
+	if instance.Status.CSCA == nil || instance.Status.CSCA.Message != message {
+		logd.Info("Rejecting CA import", "reason", reason)
+		r.updateEvent(instance, message, corev1.EventTypeWarning, "CSCAImportFailed")
+	}
+	status := &operatorv1.CSCAStatus{Message: message}
+	if instance.Status.CSCA != nil {
+		status.NotAfter = instance.Status.CSCA.NotAfter
+		status.RenewalTime = instance.Status.CSCA.RenewalTime
+		status.Imported = instance.Status.CSCA.Imported
+	}
+	return r.updateCSCAStatus(instance, status)
+}
+
+// markCSCASecretManaged marks the CS CA secret, if any, as created by the
+// operator
+func (r *CertManagerReconciler) markCSCASecretManaged() error {
+	secret := &corev1.Secret{}
+	err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCASecretName}, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, ok := secret.Annotations[res.CSCAManagedAnnotation]; ok {
+		return nil
+	}
+	secret.Annotations = mergeLabels(secret.Annotations, res.CSCAManagedAnnotationMap)
+	return r.Client.Update(context.TODO(), secret)
+}
+
+// swapCSCASecret writes the keypair of the import secret to the CS CA
+// secret, detached from the self-signed certificate that owned it. The secret
+// stays marked as created by the operator, so the self-signed chain is
+// bootstrapped again over it once the import is removed. It returns true when
+// the secret changed.
+func (r *CertManagerReconciler) swapCSCASecret(source *corev1.Secret, rootPEM []byte) (bool, error) {
+	data := map[string][]byte{
+		corev1.TLSCertKey:       source.Data[corev1.TLSCertKey],
+		corev1.TLSPrivateKeyKey: source.Data[corev1.TLSPrivateKeyKey],
+		"ca.crt":                rootPEM,
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.CSCASecretName}, secret)
+	if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        res.CSCASecretName,
+				Namespace:   r.NS,
+				Labels:      mergeLabels(res.CSCAIssuerLabelMap, map[string]string{res.SecretWatchLabel: ""}),
+				Annotations: res.CSCAManagedAnnotationMap,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		logd.Info("Creating CS CA secret from the imported CA")
+		return true, r.Client.Create(context.TODO(), secret)
+	} else if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(secret.Data[corev1.TLSCertKey], data[corev1.TLSCertKey]) &&
+		bytes.Equal(secret.Data[corev1.TLSPrivateKeyKey], data[corev1.TLSPrivateKeyKey]) &&
+		bytes.Equal(secret.Data["ca.crt"], data["ca.crt"]) && len(secret.OwnerReferences) == 0 &&
+		secret.Annotations[res.CSCAManagedAnnotation] != "" {
+		return false, nil
+	}
+	// dropping the owner keeps the secret when the self-signed certificate
+	// is deleted, and the cert-manager annotations stop cert-manager from
+	// treating it as the secret of a certificate
+	secret.OwnerReferences = nil
+	for key := range secret.Annotations {
+		if strings.HasPrefix(key, certmanagerv1.SchemeGroupVersion.Group+"/") {
+			delete(secret.Annotations, key)
+		}
+	}
+	secret.Labels = mergeLabels(secret.Labels, map[string]string{res.SecretWatchLabel: ""})
+	secret.Annotations = mergeLabels(secret.Annotations, res.CSCAManagedAnnotationMap)
+	secret.Data = data
+	logd.Info("Replacing the CS CA secret with the imported CA")
+	return true, r.Client.Update(context.TODO(), secret)
+}
+
+// validateImportedCA checks the keypair of the secret is a CA keypair valid
+// for at least minValidity, whose chain verifies up to a root. It returns the
+// CA certificate and the PEM of the root.
+func validateImportedCA(secret *corev1.Secret, minValidity time.Duration) (*x509.Certificate, []byte, error) {
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, nil, fmt.Errorf("%s and %s are required", corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	// X509KeyPair fails unless the key matches the first certificate
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, nil, fmt.Errorf("invalid keypair: %v", err)
+	}
+
+	chain, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %v", corev1.TLSCertKey, err)
+	}
+	ca := chain[0]
+	if !ca.BasicConstraintsValid || !ca.IsCA {
+		return nil, nil, fmt.Errorf("certificate %s is not a CA", ca.Subject.String())
+	}
+	if ca.KeyUsage != 0 && ca.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, nil, fmt.Errorf("certificate %s cannot sign certificates", ca.Subject.String())
+	}
+	if remaining := time.Until(ca.NotAfter); remaining < minValidity {
+		return nil, nil, fmt.Errorf("certificate %s expires on %s, less than %s from now", ca.Subject.String(),
+			ca.NotAfter.Format(time.RFC3339), minValidity)
+	}
+
+	// the root comes from ca.crt, or ends the chain in tls.crt
+	var roots []*x509.Certificate
+	if rootData := secret.Data["ca.crt"]; len(rootData) > 0 {
+		if roots, err = parseCertificates(rootData); err != nil {
+			return nil, nil, fmt.Errorf("invalid ca.crt: %v", err)
+		}
+	} else {
+		last := chain[len(chain)-1]
+		if last.CheckSignatureFrom(last) != nil {
+			return nil, nil, fmt.Errorf("the chain in %s does not end with a root CA and ca.crt is not set", corev1.TLSCertKey)
+		}
+		roots = []*x509.Certificate{last}
+	}
+
+	rootPool, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, root := range roots {
+		rootPool.AddCert(root)
+	}
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := ca.Verify(x509.VerifyOptions{
+		Roots:         rootPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, nil, fmt.Errorf("chain does not verify: %v", err)
+	}
+
+	var rootPEM []byte
+	for _, root := range roots {
+		rootPEM = append(rootPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw})...)
+	}
+	return ca, rootPEM, nil
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
@@ -0,0 +1,509 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+const testNS = "ibm-common-services"
+
+func newTestReconciler(t *testing.T, objs ...client.Object) *CertManagerReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, operatorv1.AddToScheme, certmanagerv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &CertManagerReconciler{
+		Client:   c,
+		Reader:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		NS:       testNS,
+	}
+}
+
+func deployment(name string, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func TestReconcileCSCAWaitsForOperands(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec:       operatorv1.CertManagerConfigSpec{Webhook: true},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), deployment(res.CertManagerWebhookName, 0))
+
+	ready, err := r.reconcileCSCA(instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ready {
+		t.Errorf("CS CA ready before the webhook")
+	}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, &certmanagerv1.Certificate{}); err == nil {
+		t.Errorf("CS CA certificate created before the webhook is available")
+	}
+	if instance.Status.CSCA == nil || instance.Status.CSCA.Message == "" {
+		t.Errorf("unexpected status %+v", instance.Status.CSCA)
+	}
+}
+
+func TestReconcileCSCACreatesChainAndRepairsDrift(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{KeyAlgorithm: "ECDSA", KeySize: 384, Duration: &metav1.Duration{Duration: 8760 * time.Hour}},
+		},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1))
+	ctx := context.TODO()
+
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+
+	selfSigned := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASelfSignedIssuerName}, selfSigned); err != nil {
+		t.Fatal(err)
+	}
+	if selfSigned.Spec.SelfSigned == nil {
+		t.Errorf("%s is not self-signed", res.CSCASelfSignedIssuerName)
+	}
+	caIssuer := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCAIssuerName}, caIssuer); err != nil {
+		t.Fatal(err)
+	}
+	if caIssuer.Spec.CA == nil || caIssuer.Spec.CA.SecretName != res.CSCASecretName {
+		t.Errorf("unexpected CA issuer %+v", caIssuer.Spec)
+	}
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, crt); err != nil {
+		t.Fatal(err)
+	}
+	if !crt.Spec.IsCA || crt.Spec.IssuerRef.Name != res.CSCASelfSignedIssuerName || crt.Spec.PrivateKey.Algorithm != certmanagerv1.ECDSAKeyAlgorithm ||
+		crt.Spec.PrivateKey.Size != 384 || crt.Spec.Duration.Duration != 8760*time.Hour || crt.Spec.RenewBefore.Duration != res.DefaultCSCARenewBefore {
+		t.Errorf("unexpected CA certificate %+v", crt.Spec)
+	}
+	if instance.Status.CSCA == nil || instance.Status.CSCA.Ready {
+		t.Errorf("unexpected status %+v", instance.Status.CSCA)
+	}
+
+	// an edited CA issuer is put back
+	caIssuer.Spec.CA.SecretName = "other-secret"
+	if err := r.Client.Update(ctx, caIssuer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	caIssuer = &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCAIssuerName}, caIssuer); err != nil {
+		t.Fatal(err)
+	}
+	if caIssuer.Spec.CA.SecretName != res.CSCASecretName {
+		t.Errorf("drifted CA issuer not repaired: %+v", caIssuer.Spec.CA)
+	}
+}
+
+type keypair struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newKeypair(t *testing.T, cn string, isCA bool, validity time.Duration, parent *keypair) *keypair {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(validity),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &keypair{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func importSecret(certPEM, keyPEM, caPEM []byte) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "corporate-ca", Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}
+	if caPEM != nil {
+		secret.Data["ca.crt"] = caPEM
+	}
+	return secret
+}
+
+func TestValidateImportedCA(t *testing.T) {
+	root := newKeypair(t, "corporate-root", true, 10*365*24*time.Hour, nil)
+	intermediate := newKeypair(t, "corporate-intermediate", true, 2*365*24*time.Hour, root)
+	otherRoot := newKeypair(t, "other-root", true, 10*365*24*time.Hour, nil)
+	leaf := newKeypair(t, "leaf", false, 365*24*time.Hour, root)
+	expiring := newKeypair(t, "expiring", true, 24*time.Hour, root)
+
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		valid  bool
+	}{
+		{"intermediate with root in ca.crt", importSecret(intermediate.certPEM, intermediate.keyPEM, root.certPEM), true},
+		{"intermediate with root ending the chain", importSecret(append(intermediate.certPEM, root.certPEM...), intermediate.keyPEM, nil), true},
+		{"self-signed root", importSecret(root.certPEM, root.keyPEM, nil), true},
+		{"intermediate without root", importSecret(intermediate.certPEM, intermediate.keyPEM, nil), false},
+		{"untrusted root", importSecret(intermediate.certPEM, intermediate.keyPEM, otherRoot.certPEM), false},
+		{"key mismatch", importSecret(intermediate.certPEM, root.keyPEM, root.certPEM), false},
+		{"not a CA", importSecret(leaf.certPEM, leaf.keyPEM, root.certPEM), false},
+		{"expiring soon", importSecret(expiring.certPEM, expiring.keyPEM, root.certPEM), false},
+		{"missing key", importSecret(intermediate.certPEM, nil, root.certPEM), false},
+	}
+	for _, tt := range tests {
+		_, rootPEM, err := validateImportedCA(tt.secret, res.DefaultCSCAImportMinValidity)
+		if tt.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: imported an invalid CA", tt.name)
+		}
+		if tt.valid && string(rootPEM) != string(root.certPEM) {
+			t.Errorf("%s: got root %q", tt.name, rootPEM)
+		}
+	}
+}
+
+func TestReconcileCSCAImportsCA(t *testing.T) {
+	root := newKeypair(t, "corporate-root", true, 10*365*24*time.Hour, nil)
+	intermediate := newKeypair(t, "corporate-intermediate", true, 2*365*24*time.Hour, root)
+	controller := true
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{Import: &operatorv1.CSCAImport{SecretName: "corporate-ca"}},
+		},
+	}
+	selfSigned := csCACertificate(instance, testNS)
+	csCASecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            res.CSCASecretName,
+			Namespace:       testNS,
+			Annotations:     map[string]string{"cert-manager.io/certificate-name": res.CSCACertName},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "cert-manager.io/v1", Kind: "Certificate", Name: res.CSCACertName, UID: "uid", Controller: &controller}},
+		},
+		Data: map[string][]byte{corev1.TLSCertKey: []byte("self-signed")},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), selfSigned, csCASecret,
+		importSecret(intermediate.certPEM, intermediate.keyPEM, root.certPEM))
+	ctx := context.TODO()
+
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != string(intermediate.certPEM) || string(secret.Data["ca.crt"]) != string(root.certPEM) {
+		t.Errorf("CS CA secret not swapped: %v", secret.Data)
+	}
+	if len(secret.OwnerReferences) != 0 || secret.Annotations["cert-manager.io/certificate-name"] != "" {
+		t.Errorf("CS CA secret still tied to the self-signed certificate: %+v", secret.ObjectMeta)
+	}
+	if _, ok := secret.Annotations[res.CSCAManagedAnnotation]; !ok {
+		t.Errorf("CS CA secret not marked as created by the operator")
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("CS CA secret not labelled for the refresh controller")
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, &certmanagerv1.Certificate{}); !errors.IsNotFound(err) {
+		t.Errorf("self-signed CS CA certificate still managed: %v", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCAIssuerName}, &certmanagerv1.Issuer{}); err != nil {
+		t.Errorf("CS CA issuer not created: %v", err)
+	}
+	if instance.Status.CSCA == nil || !instance.Status.CSCA.Imported || instance.Status.CSCA.NotAfter == nil {
+		t.Errorf("unexpected status %+v", instance.Status.CSCA)
+	}
+
+	// an invalid import leaves the CS CA secret alone
+	source := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: "corporate-ca"}, source); err != nil {
+		t.Fatal(err)
+	}
+	source.Data[corev1.TLSPrivateKeyKey] = root.keyPEM
+	if err := r.Client.Update(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	secret = &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSPrivateKeyKey]) != string(intermediate.keyPEM) {
+		t.Errorf("invalid import replaced the CS CA key")
+	}
+	if instance.Status.CSCA.Ready || instance.Status.CSCA.Message == "" {
+		t.Errorf("invalid import not reported: %+v", instance.Status.CSCA)
+	}
+}
+
+func TestReconcileCSCAImportWaitsForCertificateDeletion(t *testing.T) {
+	root := newKeypair(t, "corporate-root", true, 10*365*24*time.Hour, nil)
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{Import: &operatorv1.CSCAImport{SecretName: "corporate-ca"}},
+		},
+	}
+	selfSigned := csCACertificate(instance, testNS)
+	selfSigned.Finalizers = []string{"example.com/finalizer"}
+	csCASecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("self-signed")},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), selfSigned, csCASecret,
+		importSecret(root.certPEM, root.keyPEM, nil))
+	ctx := context.TODO()
+
+	// cert-manager could still re-issue the self-signed CA into the secret
+	if ready, err := r.reconcileCSCA(instance); err != nil || ready {
+		t.Fatalf("got ready %t, error %v while the certificate is being deleted", ready, err)
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != "self-signed" {
+		t.Errorf("CS CA secret swapped before the certificate was deleted")
+	}
+	if instance.Status.CSCA == nil || instance.Status.CSCA.Message == "" {
+		t.Errorf("waiting for the deletion not reported: %+v", instance.Status.CSCA)
+	}
+
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, crt); err != nil {
+		t.Fatal(err)
+	}
+	crt.Finalizers = nil
+	if err := r.Client.Update(ctx, crt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	secret = &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != string(root.certPEM) {
+		t.Errorf("CS CA secret not swapped once the certificate was deleted")
+	}
+}
+
+func TestReconcileCSCAImportLeavesUnmanagedChainAlone(t *testing.T) {
+	root := newKeypair(t, "corporate-root", true, 10*365*24*time.Hour, nil)
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{Import: &operatorv1.CSCAImport{SecretName: "corporate-ca"}},
+		},
+	}
+	// the chain was created by another component
+	foreignCert := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCACertName, Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: res.CSCASecretName, IsCA: true},
+	}
+	foreignIssuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: "platform-ca"},
+		}},
+	}
+	csCASecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("platform")},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), foreignCert, foreignIssuer, csCASecret,
+		importSecret(root.certPEM, root.keyPEM, nil))
+	ctx := context.TODO()
+
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, &certmanagerv1.Certificate{}); err != nil {
+		t.Errorf("unmanaged CS CA certificate deleted: %v", err)
+	}
+	issuer := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCAIssuerName}, issuer); err != nil {
+		t.Fatal(err)
+	}
+	if issuer.Spec.CA.SecretName != "platform-ca" {
+		t.Errorf("unmanaged issuer overwritten: %+v", issuer.Spec)
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data[corev1.TLSCertKey]) != "platform" {
+		t.Errorf("unmanaged CS CA secret swapped")
+	}
+	if instance.Status.CSCA == nil || !strings.HasPrefix(instance.Status.CSCA.Message, "Cannot import CA") ||
+		!strings.Contains(instance.Status.CSCA.Message, "Issuer "+res.CSCAIssuerName) {
+		t.Errorf("import not rejected: %+v", instance.Status.CSCA)
+	}
+}
+
+func TestReconcileCSCABootstrapsAgainAfterImport(t *testing.T) {
+	root := newKeypair(t, "corporate-root", true, 10*365*24*time.Hour, nil)
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CSCA: &operatorv1.CSCASpec{Import: &operatorv1.CSCAImport{SecretName: "corporate-ca"}},
+		},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), importSecret(root.certPEM, root.keyPEM, nil))
+	ctx := context.TODO()
+
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASecretName}, &corev1.Secret{}); err != nil {
+		t.Fatalf("CS CA secret not created from the import: %v", err)
+	}
+
+	// without the import, the secret the operator created is taken over by
+	// the self-signed certificate again
+	instance.Spec.CSCA.Import = nil
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, crt); err != nil {
+		t.Fatalf("self-signed CS CA certificate not created again: %v", err)
+	}
+	if crt.Spec.SecretName != res.CSCASecretName {
+		t.Errorf("got secret %s for the CS CA certificate", crt.Spec.SecretName)
+	}
+	if instance.Status.CSCA == nil || strings.Contains(instance.Status.CSCA.Message, "not created by the operator") {
+		t.Errorf("CS CA secret of the import reported as unmanaged: %+v", instance.Status.CSCA)
+	}
+}
+
+func TestReconcileCSCALeavesUnmanagedChainAlone(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	// cs-ca-issuer created by another component, with its own CA secret
+	foreign := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: "platform-ca"},
+		}},
+	}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), foreign)
+	ctx := context.TODO()
+
+	if ready, err := r.reconcileCSCA(instance); err != nil || ready {
+		t.Fatalf("got ready %t, error %v", ready, err)
+	}
+	issuer := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCAIssuerName}, issuer); err != nil {
+		t.Fatal(err)
+	}
+	if issuer.Spec.CA.SecretName != "platform-ca" || len(issuer.Labels) != 0 {
+		t.Errorf("unmanaged issuer adopted: %+v", issuer)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCASelfSignedIssuerName}, &certmanagerv1.Issuer{}); !errors.IsNotFound(err) {
+		t.Errorf("self-signed issuer created next to the unmanaged chain: %v", err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, &certmanagerv1.Certificate{}); !errors.IsNotFound(err) {
+		t.Errorf("CA certificate created next to the unmanaged chain: %v", err)
+	}
+	if instance.Status.CSCA == nil || !strings.Contains(instance.Status.CSCA.Message, "Issuer "+res.CSCAIssuerName) {
+		t.Errorf("unmanaged chain not reported: %+v", instance.Status.CSCA)
+	}
+
+	// a CA secret without its certificate is not overwritten either
+	r = newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS},
+	})
+	if _, err := r.reconcileCSCA(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.CSCACertName}, &certmanagerv1.Certificate{}); !errors.IsNotFound(err) {
+		t.Errorf("certificate created over an existing CA secret: %v", err)
+	}
+}
@@ -22,11 +22,13 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
 	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -49,6 +51,7 @@ func webhookDeploy(instance *operatorv1.CertManagerConfig, client client.Client,
 }
 
 func deployLogic(instance *operatorv1.CertManagerConfig, client client.Client, kubeclient kubernetes.Interface, scheme *runtime.Scheme, deployTemplate *appsv1.Deployment, name, imageName, labels, ns string) error {
+	defer observePhase("deployment/"+name, time.Now())
 	similarDeploys := deployFinder(kubeclient, labels, imageName)
 	deployment := setupDeploy(instance, deployTemplate, ns)
 	var existingDeploy appsv1.Deployment
@@ -65,6 +68,7 @@ func deployLogic(instance *operatorv1.CertManagerConfig, client client.Client, k
 				name, deploy.Namespace, deploy.Name, name)
 			logd.V(4).Info(errMsg)
 			err := errors.New(errMsg)
+			deploymentConflicts.WithLabelValues(name).Inc()
 			return err
 		}
 		// Otherwise one exists so we update it
@@ -81,13 +85,14 @@ func deployLogic(instance *operatorv1.CertManagerConfig, client client.Client, k
 			return err
 		}
 	} else {
-		if !equalDeploys(deployment, existingDeploy) {
+		if reason := deployDiff(deployment, existingDeploy); reason != "" {
 			// Update
-			logd.V(2).Info("Updating deployment")
+			logd.V(2).Info("Updating deployment", "reason", reason)
 			deployment.SetResourceVersion(existingDeploy.GetResourceVersion())
 			if err := client.Update(context.Background(), &deployment); err != nil {
 				return err
 			}
+			recordUpdate("Deployment", reason)
 		} else {
 			logd.V(3).Info("Deploys are equal, no changes needed")
 		}
@@ -104,7 +109,7 @@ func deployLogic(instance *operatorv1.CertManagerConfig, client client.Client, k
 func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployment, ns string) appsv1.Deployment {
 	// First copy the deploy template into a deployment object
 
-	returningDeploy := *deploy
+	returningDeploy := *deploy.DeepCopy()
 
 	imageRegistry := res.ImageRegistry
 	if instance.Spec.ImageRegistry != "" {
@@ -124,7 +129,17 @@ func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployme
 		copy(args, res.DefaultArgs)
 		args = append(args, acmesolver, resourceNS, leaderElect)
 		returningDeploy.Spec.Template.Spec.Containers[0].Args = args
-		logd.V(3).Info("The args", "args", deploy.Spec.Template.Spec.Containers[0].Args)
+		logd.V(3).Info("The args", "args", returningDeploy.Spec.Template.Spec.Containers[0].Args)
+
+		//expose the metrics port only when monitoring is enabled
+		returningDeploy.Spec.Template.Spec.Containers[0].Ports = nil
+		if monitoringEnabled(instance) {
+			returningDeploy.Spec.Template.Spec.Containers[0].Ports = []corev1.ContainerPort{{
+				Name:          res.ControllerMetricsPortName,
+				ContainerPort: res.ControllerMetricsPort,
+				Protocol:      corev1.ProtocolTCP,
+			}}
+		}
 
 		//add resource limits and requests for controller only if present in CR else use default as defined in constants.go
 		if instance.Spec.CertManagerController.Resources.Limits != nil {
@@ -133,6 +148,7 @@ func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployme
 		if instance.Spec.CertManagerController.Resources.Requests != nil {
 			returningDeploy.Spec.Template.Spec.Containers[0].Resources.Requests = instance.Spec.CertManagerController.Resources.Requests
 		}
+		setProbeTimings(&returningDeploy.Spec.Template.Spec.Containers[0], instance.Spec.CertManagerController)
 
 	case res.CertManagerCainjectorName:
 		returningDeploy.Spec.Template.Spec.Containers[0].Image = res.GetImageID(imageRegistry, res.CainjectorImageName, res.ControllerImageVersion, instance.Spec.ImagePostFix, res.CaInjectorImageEnvVar)
@@ -148,6 +164,7 @@ func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployme
 		if instance.Spec.CertManagerCAInjector.Resources.Requests != nil {
 			returningDeploy.Spec.Template.Spec.Containers[0].Resources.Requests = instance.Spec.CertManagerCAInjector.Resources.Requests
 		}
+		setProbeTimings(&returningDeploy.Spec.Template.Spec.Containers[0], instance.Spec.CertManagerCAInjector)
 
 	case res.CertManagerWebhookName:
 		returningDeploy.Spec.Template.Spec.Containers[0].Image = res.GetImageID(imageRegistry, res.WebhookImageName, res.WebhookImageVersion, instance.Spec.ImagePostFix, res.WebhookImageEnvVar)
@@ -164,6 +181,7 @@ func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployme
 		if instance.Spec.CertManagerWebhook.Resources.Requests != nil {
 			returningDeploy.Spec.Template.Spec.Containers[0].Resources.Requests = instance.Spec.CertManagerWebhook.Resources.Requests
 		}
+		setProbeTimings(&returningDeploy.Spec.Template.Spec.Containers[0], instance.Spec.CertManagerWebhook)
 	}
 
 	returningDeploy.Namespace = ns
@@ -172,6 +190,85 @@ func setupDeploy(instance *operatorv1.CertManagerConfig, deploy *appsv1.Deployme
 	return returningDeploy
 }
 
+// setProbeTimings overrides the timings of the probes of the container with
+// those set in the CR
+func setProbeTimings(container *corev1.Container, spec operatorv1.CertManagerContainerSpec) {
+	probeTimings(container.LivenessProbe, spec.LivenessProbe)
+	probeTimings(container.ReadinessProbe, spec.ReadinessProbe)
+}
+
+func probeTimings(probe *corev1.Probe, timings *operatorv1.ProbeTimings) {
+	if probe == nil || timings == nil {
+		return
+	}
+	if timings.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *timings.InitialDelaySeconds
+	}
+	if timings.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *timings.TimeoutSeconds
+	}
+	if timings.PeriodSeconds != nil {
+		probe.PeriodSeconds = *timings.PeriodSeconds
+	}
+	if timings.FailureThreshold != nil {
+		probe.FailureThreshold = *timings.FailureThreshold
+	}
+}
+
+// equalProbes compares the handlers and the timings of two probes, whichever
+// kind of handler they use
+func equalProbes(kind string, first, second *corev1.Probe) bool {
+	statusLog := logd.V(1)
+	if first == nil || second == nil {
+		if first != second {
+			statusLog.Info("One of the "+kind+" probes is nil",
+				"first", fmt.Sprintf("%v", first), "second", fmt.Sprintf("%v", second))
+			return false
+		}
+		return true
+	}
+
+	fExec, sExec := first.Handler.Exec, second.Handler.Exec
+	if (fExec == nil) != (sExec == nil) || fExec != nil && !reflect.DeepEqual(fExec.Command, sExec.Command) {
+		statusLog.Info("Exec command in "+kind+" probes not equal",
+			"first", fmt.Sprintf("%v", fExec), "second", fmt.Sprintf("%v", sExec))
+		return false
+	}
+
+	fGet, sGet := first.Handler.HTTPGet, second.Handler.HTTPGet
+	if (fGet == nil) != (sGet == nil) || fGet != nil && (fGet.Path != sGet.Path || fGet.Port != sGet.Port ||
+		uriScheme(fGet.Scheme) != uriScheme(sGet.Scheme) || !reflect.DeepEqual(fGet.HTTPHeaders, sGet.HTTPHeaders)) {
+		statusLog.Info("HTTP get in "+kind+" probes not equal",
+			"first", fmt.Sprintf("%v", fGet), "second", fmt.Sprintf("%v", sGet))
+		return false
+	}
+
+	fTCP, sTCP := first.Handler.TCPSocket, second.Handler.TCPSocket
+	if (fTCP == nil) != (sTCP == nil) || fTCP != nil && fTCP.Port != sTCP.Port {
+		statusLog.Info("TCP socket in "+kind+" probes not equal",
+			"first", fmt.Sprintf("%v", fTCP), "second", fmt.Sprintf("%v", sTCP))
+		return false
+	}
+
+	if first.InitialDelaySeconds != second.InitialDelaySeconds || first.TimeoutSeconds != second.TimeoutSeconds ||
+		first.PeriodSeconds != second.PeriodSeconds || first.FailureThreshold != second.FailureThreshold {
+		statusLog.Info("Timings in "+kind+" probes not equal",
+			"first", fmt.Sprintf("%d/%d/%d/%d", first.InitialDelaySeconds, first.TimeoutSeconds, first.PeriodSeconds, first.FailureThreshold),
+			"second", fmt.Sprintf("%d/%d/%d/%d", second.InitialDelaySeconds, second.TimeoutSeconds, second.PeriodSeconds, second.FailureThreshold))
+		return false
+	}
+	return true
+}
+
+// uriScheme returns the scheme of an HTTP get probe, HTTP when it is not set
+// as the API server defaults it
+func uriScheme(scheme corev1.URIScheme) corev1.URIScheme {
+	if scheme == "" {
+		return corev1.URISchemeHTTP
+	}
+	return scheme
+}
+
 func removeDeploy(client kubernetes.Interface, name, namespace string) error {
 	if err := client.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
 		logd.V(1).Info("Error removing deployment", "name", name, "namespace", namespace, "error message", err)
@@ -234,21 +331,27 @@ func deployFinder(client kubernetes.Interface, labels, name string) []appsv1.Dep
 
 // Deep comparison between the two deployments passed in
 // Checks labels, replicas, pod template labels, pull secrets, service account names,
-// volumes, liveness, readiness, image name, args, env, and security contexts (pod & container)
+// volumes, ports, liveness, readiness, image name, args, env, and security contexts (pod & container)
 // of both deployments. If there are any discrepencies between them, this returns false. Returns
 // true otherwise
 func equalDeploys(first, second appsv1.Deployment) bool {
+	return deployDiff(first, second) == ""
+}
+
+// deployDiff returns the first field found different between the two
+// deployments, in the order equalDeploys checks them, or "" when they are equal
+func deployDiff(first, second appsv1.Deployment) string {
 	statusLog := logd.V(1)
 	if !isSubset(first.ObjectMeta.Labels, second.ObjectMeta.Labels) {
 		statusLog.Info("Labels not equal",
 			"first", fmt.Sprintf("%v", first.ObjectMeta.Labels),
 			"second", fmt.Sprintf("%v", second.ObjectMeta.Labels))
-		return false
+		return "labels"
 	}
 
 	if !reflect.DeepEqual(first.Spec.Replicas, second.Spec.Replicas) {
 		statusLog.Info("Replicas not equal", "first", first.Spec.Replicas, "second", second.Spec.Replicas)
-		return false
+		return "replicas"
 	}
 
 	firstPodTemplate := first.Spec.Template
@@ -257,28 +360,28 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 		statusLog.Info("Pod labels not equal",
 			"first", fmt.Sprintf("%v", firstPodTemplate.ObjectMeta.Labels),
 			"second", fmt.Sprintf("%v", secondPodTemplate.ObjectMeta.Labels))
-		return false
+		return "podLabels"
 	}
 
 	if !reflect.DeepEqual(firstPodTemplate.Spec.ImagePullSecrets, secondPodTemplate.Spec.ImagePullSecrets) {
 		statusLog.Info("Image pull secrets not equal",
 			"first", fmt.Sprintf("%v", firstPodTemplate.Spec.ImagePullSecrets),
 			"second", fmt.Sprintf("%v", secondPodTemplate.Spec.ImagePullSecrets))
-		return false
+		return "imagePullSecrets"
 	}
 
 	if !reflect.DeepEqual(firstPodTemplate.Spec.ServiceAccountName, secondPodTemplate.Spec.ServiceAccountName) {
 		statusLog.Info("Service account names not equal",
 			"first", firstPodTemplate.Spec.ServiceAccountName,
 			"second", secondPodTemplate.Spec.ServiceAccountName)
-		return false
+		return "serviceAccountName"
 	}
 
 	if !reflect.DeepEqual(firstPodTemplate.Spec.SecurityContext, secondPodTemplate.Spec.SecurityContext) {
 		statusLog.Info("Security context not equal",
 			"first", fmt.Sprintf("%v", firstPodTemplate.Spec.SecurityContext),
 			"second", fmt.Sprintf("%v", secondPodTemplate.Spec.SecurityContext))
-		return false
+		return "podSecurityContext"
 	}
 	fVol := firstPodTemplate.Spec.Volumes
 	sVol := secondPodTemplate.Spec.Volumes
@@ -288,28 +391,28 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 				if !reflect.DeepEqual(fVol[i].Name, sVol[i].Name) {
 					statusLog.Info("Pod volume names not equal", "volume num", i,
 						"first", fVol[i].Name, "second", sVol[i].Name)
-					return false
+					return "volumes"
 				}
 				if fVol[i].VolumeSource.Secret != nil && sVol[i].VolumeSource.Secret != nil {
 					if !reflect.DeepEqual(fVol[i].VolumeSource.Secret.SecretName, sVol[i].VolumeSource.Secret.SecretName) {
 						statusLog.Info("Volume source secret name not equal", "volume num", i,
 							"first", fVol[i].VolumeSource.Secret.SecretName, "second", sVol[i].VolumeSource.Secret.SecretName)
-						return false
+						return "volumes"
 					}
 				} else if !(fVol[i].VolumeSource.Secret == nil && sVol[i].VolumeSource.Secret == nil) {
 					statusLog.Info("One of the volume sources secrets is nil")
-					return false
+					return "volumes"
 				}
 			}
 		}
 	} else {
 		statusLog.Info("Volume lengths not equal")
-		return false
+		return "volumes"
 	}
 
 	if firstPodTemplate.Spec.HostNetwork != secondPodTemplate.Spec.HostNetwork {
 		statusLog.Info("Host networks are not equal")
-		return false
+		return "hostNetwork"
 	}
 
 	// Container level checks
@@ -318,95 +421,56 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 	if len(firstContainers) != len(secondContainers) {
 		statusLog.Info("Number of containers not equal",
 			"first", len(firstContainers), "second", len(secondContainers))
-		return false
+		return "containers"
 	}
 
 	fContainer := firstContainers[0]
 	sContainer := secondContainers[0]
 	if !reflect.DeepEqual(fContainer.Name, sContainer.Name) {
 		statusLog.Info("Container names not equal", "first", fContainer.Name, "second", sContainer.Name)
-		return false
+		return "containerName"
 	}
 
 	if !reflect.DeepEqual(fContainer.Image, sContainer.Image) {
 		statusLog.Info("Container images not equal", "first", fContainer.Image, "second", sContainer.Image)
-		return false
+		return "image"
 	}
 
 	if !reflect.DeepEqual(fContainer.ImagePullPolicy, sContainer.ImagePullPolicy) {
 		statusLog.Info("Image pull policies not equal",
 			"first", fContainer.ImagePullPolicy, "second", sContainer.ImagePullPolicy)
-		return false
+		return "imagePullPolicy"
 	}
 
 	if fContainer.Args != nil && sContainer.Args != nil {
 		if !reflect.DeepEqual(len(fContainer.Args), len(sContainer.Args)) {
 			statusLog.Info("Args length not equal",
 				"first", len(fContainer.Args), "second", len(sContainer.Args))
-			return false
+			return "args"
 		}
 		if !reflect.DeepEqual(fContainer.Args, sContainer.Args) {
 			statusLog.Info("Args not equal",
 				"first", fmt.Sprintf("%v", fContainer.Args), "second", fmt.Sprintf("%v", sContainer.Args))
-			return false
+			return "args"
 		}
 	} else if !(fContainer.Args == nil && sContainer.Args == nil) {
 		statusLog.Info("One of the args is nil",
 			"first", fmt.Sprintf("%v", fContainer.Args), "second", fmt.Sprintf("%v", sContainer.Args))
-		return false
+		return "args"
 	}
 
-	fLive := fContainer.LivenessProbe
-	sLive := sContainer.LivenessProbe
-
-	if fLive != nil && sLive != nil {
-		if !reflect.DeepEqual(fLive.Handler.Exec.Command, sLive.Handler.Exec.Command) {
-			statusLog.Info("Exec command in liveness probes not equal",
-				"first", fLive.Handler.Exec.Command, "second", sLive.Handler.Exec.Command)
-			return false
-		}
-
-		if !reflect.DeepEqual(fLive.InitialDelaySeconds, sLive.InitialDelaySeconds) {
-			statusLog.Info("Initial delay seconds in liveness probes not equal",
-				"first", fLive.InitialDelaySeconds, "second", sLive.InitialDelaySeconds)
-			return false
-		}
-
-		if !reflect.DeepEqual(fLive.TimeoutSeconds, sLive.TimeoutSeconds) {
-			statusLog.Info("Timeout seconds in liveness probes not equal",
-				"first", fLive.TimeoutSeconds, "second", sLive.TimeoutSeconds)
-			return false
-		}
-	} else if !(fLive == nil && sLive == nil) {
-		statusLog.Info("One liveness probe is nil",
-			"first", fmt.Sprintf("%v", fLive), "second", fmt.Sprintf("%v", sLive))
-		return false
+	if len(fContainer.Ports) != len(sContainer.Ports) || len(fContainer.Ports) > 0 && !reflect.DeepEqual(fContainer.Ports, sContainer.Ports) {
+		statusLog.Info("Container ports not equal",
+			"first", fmt.Sprintf("%v", fContainer.Ports), "second", fmt.Sprintf("%v", sContainer.Ports))
+		return "ports"
 	}
 
-	fReady := fContainer.ReadinessProbe
-	sReady := sContainer.ReadinessProbe
-	if fReady != nil && sReady != nil {
-		if !reflect.DeepEqual(fReady.Handler.Exec.Command, sReady.Handler.Exec.Command) {
-			statusLog.Info("Exec command in readiness probes not equal",
-				"first", fReady.Handler.Exec.Command, "second", sReady.Handler.Exec.Command)
-			return false
-		}
-
-		if !reflect.DeepEqual(fReady.InitialDelaySeconds, sReady.InitialDelaySeconds) {
-			statusLog.Info("Initial delay seconds in readiness probes not equal",
-				"first", fReady.InitialDelaySeconds, "second", sReady.InitialDelaySeconds)
-			return false
-		}
+	if !equalProbes("liveness", fContainer.LivenessProbe, sContainer.LivenessProbe) {
+		return "livenessProbe"
+	}
 
-		if !reflect.DeepEqual(fReady.TimeoutSeconds, sReady.TimeoutSeconds) {
-			statusLog.Info("Timeout seconds in readiness probes not equal",
-				"first", fReady.TimeoutSeconds, "second", sReady.TimeoutSeconds)
-			return false
-		}
-	} else if !(fReady == nil && sReady == nil) {
-		statusLog.Info("One of the readiness probes is nil",
-			"first", fmt.Sprintf("%v", fReady), "second", fmt.Sprintf("%v", sReady))
-		return false
+	if !equalProbes("readiness", fContainer.ReadinessProbe, sContainer.ReadinessProbe) {
+		return "readinessProbe"
 	}
 
 	fSecCont := fContainer.SecurityContext
@@ -417,70 +481,70 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 			if !reflect.DeepEqual(fSecCont.RunAsNonRoot, sSecCont.RunAsNonRoot) {
 				statusLog.Info("Container security context run as non root not equal",
 					"first", fSecCont.RunAsNonRoot, "second", sSecCont.RunAsNonRoot)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.RunAsNonRoot == nil && sSecCont.RunAsNonRoot == nil) {
 			statusLog.Info("One security context run as non root is nil")
-			return false
+			return "securityContext"
 		}
 
 		if fSecCont.RunAsUser != nil && sSecCont.RunAsUser != nil {
 			if !reflect.DeepEqual(fSecCont.RunAsUser, sSecCont.RunAsUser) {
 				statusLog.Info("Container security context run as user not equal",
 					"first", fSecCont.RunAsUser, "second", sSecCont.RunAsUser)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.RunAsUser == nil && sSecCont.RunAsUser == nil) {
 			statusLog.Info("One security context run as user is nil")
-			return false
+			return "securityContext"
 		}
 
 		if fSecCont.AllowPrivilegeEscalation != nil && sSecCont.AllowPrivilegeEscalation != nil {
 			if !reflect.DeepEqual(fSecCont.AllowPrivilegeEscalation, sSecCont.AllowPrivilegeEscalation) {
 				statusLog.Info("Container security context AllowPrivilegeEscalation not equal",
 					"first", fSecCont.AllowPrivilegeEscalation, "second", sSecCont.AllowPrivilegeEscalation)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.AllowPrivilegeEscalation == nil && sSecCont.AllowPrivilegeEscalation == nil) {
 			statusLog.Info("One security context AllowPrivilegeEscalation is nil")
-			return false
+			return "securityContext"
 		}
 
 		if fSecCont.ReadOnlyRootFilesystem != nil && sSecCont.ReadOnlyRootFilesystem != nil {
 			if !reflect.DeepEqual(fSecCont.ReadOnlyRootFilesystem, sSecCont.ReadOnlyRootFilesystem) {
 				statusLog.Info("Container security context ReadOnlyRootFilesystem not equal",
 					"first", fSecCont.ReadOnlyRootFilesystem, "second", sSecCont.ReadOnlyRootFilesystem)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.ReadOnlyRootFilesystem == nil && sSecCont.ReadOnlyRootFilesystem == nil) {
 			statusLog.Info("One security context ReadOnlyRootFilesystem is nil")
-			return false
+			return "securityContext"
 		}
 
 		if fSecCont.Privileged != nil && sSecCont.Privileged != nil {
 			if !reflect.DeepEqual(fSecCont.Privileged, sSecCont.Privileged) {
 				statusLog.Info("Container security context Privileged not equal",
 					"first", fSecCont.Privileged, "second", sSecCont.Privileged)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.Privileged == nil && sSecCont.Privileged == nil) {
 			statusLog.Info("One security context Privileged is nil")
-			return false
+			return "securityContext"
 		}
 
 		if fSecCont.Capabilities != nil && sSecCont.Capabilities != nil {
 			if !reflect.DeepEqual(fSecCont.Capabilities, sSecCont.Capabilities) {
 				statusLog.Info("Container security context Capabilities not equal",
 					"first", fSecCont.Capabilities, "second", sSecCont.Capabilities)
-				return false
+				return "securityContext"
 			}
 		} else if !(fSecCont.Capabilities == nil && sSecCont.Capabilities == nil) {
 			statusLog.Info("One security context Capabilities is nil")
-			return false
+			return "securityContext"
 		}
 	} else if !(fSecCont == nil && sSecCont == nil) {
 		statusLog.Info("One security context is nil")
-		return false
+		return "securityContext"
 	}
 
 	fRes := fContainer.Resources
@@ -489,43 +553,43 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 	if fmt.Sprint(fRes.Limits.Cpu().AsDec()) != fmt.Sprint(sRes.Limits.Cpu().AsDec()) {
 		statusLog.Info("Resource limit cpu not equal",
 			"first", fmt.Sprint(fRes.Limits.Cpu().AsDec()), "second", fmt.Sprint(sRes.Limits.Cpu().AsDec()))
-		return false
+		return "resources"
 	}
 
 	if fmt.Sprint(fRes.Limits.Memory().AsDec()) != fmt.Sprint(sRes.Limits.Memory().AsDec()) {
 		statusLog.Info("Resource limit memory not equal",
 			"first", fmt.Sprint(fRes.Limits.Memory().AsDec()), "second", fmt.Sprint(sRes.Limits.Memory().AsDec()))
-		return false
+		return "resources"
 	}
 
 	if fmt.Sprint(fRes.Requests.Cpu().AsDec()) != fmt.Sprint(sRes.Requests.Cpu().AsDec()) {
 		statusLog.Info("Resource requests cpu not equal",
 			"first", fmt.Sprint(fRes.Requests.Cpu().AsDec()), "second", fmt.Sprint(sRes.Requests.Cpu().AsDec()))
-		return false
+		return "resources"
 	}
 
 	if fmt.Sprint(fRes.Requests.Memory().AsDec()) != fmt.Sprint(sRes.Requests.Memory().AsDec()) {
 		statusLog.Info("Resource requests memory not equal",
 			"first", fmt.Sprint(fRes.Requests.Memory().AsDec()), "second", fmt.Sprint(sRes.Requests.Memory().AsDec()))
-		return false
+		return "resources"
 	}
 
 	fEnv := fContainer.Env
 	sEnv := sContainer.Env
 	if !reflect.DeepEqual(len(fEnv), len(sEnv)) {
 		statusLog.Info("Environment var length not equal")
-		return false
+		return "env"
 	} else if len(fEnv) > 0 {
 		for i := range fEnv {
 			if !reflect.DeepEqual(fEnv[i].Name, sEnv[i].Name) {
 				statusLog.Info("Container number", "first", i)
 				statusLog.Info("Environment names not equal", "first", fEnv[i].Name, "second", sEnv[i].Name)
-				return false
+				return "env"
 			}
 			if !reflect.DeepEqual(fEnv[i].Value, sEnv[i].Value) {
 				statusLog.Info("Container number", "first", i)
 				statusLog.Info("Environment values not equal", "first", fEnv[i].Value, "second", sEnv[i].Value)
-				return false
+				return "env"
 			}
 			if fEnv[i].ValueFrom != nil && sEnv[i].ValueFrom != nil {
 				fFieldRef := fEnv[i].ValueFrom.FieldRef
@@ -534,18 +598,18 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 					if !reflect.DeepEqual(fEnv[i].ValueFrom.FieldRef.FieldPath, sEnv[i].ValueFrom.FieldRef.FieldPath) {
 						statusLog.Info("Field path in env not equal",
 							"first", fEnv[i].ValueFrom.FieldRef.FieldPath, "second", sEnv[i].ValueFrom.FieldRef.FieldPath)
-						return false
+						return "env"
 					}
 				} else if !(fFieldRef == nil && sFieldRef == nil) {
 					statusLog.Info("Container number", "first", i)
 					statusLog.Info("One of the env's field ref is nil")
-					return false
+					return "env"
 				}
 
 			} else if !(fEnv[i].ValueFrom == nil && sEnv[i].ValueFrom == nil) {
 				statusLog.Info("Container number", "first", i)
 				statusLog.Info("One of the env's value from is nil")
-				return false
+				return "env"
 			}
 		}
 	}
@@ -557,17 +621,17 @@ func equalDeploys(first, second appsv1.Deployment) bool {
 				if !reflect.DeepEqual(fVolMnt[i], sVolMnt[i]) {
 					statusLog.Info("Volume mounts not equal", "num", i,
 						"first", fmt.Sprintf("%v", fVolMnt[i]), "second", fmt.Sprintf("%v", sVolMnt[i]))
-					return false
+					return "volumeMounts"
 				}
 			}
 		}
 	} else {
 		statusLog.Info("Volume mount lengths not equal")
-		return false
+		return "volumeMounts"
 	}
 
 	logd.V(2).Info("Finished checking for differences between the deployments and found none.", "deployment name", first.Name)
-	return true
+	return ""
 }
 
 func isSubset(first, second map[string]string) bool {
@@ -0,0 +1,105 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestSetupDeployProbeTimings(t *testing.T) {
+	period := int32(5)
+	threshold := int32(3)
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			CertManagerWebhook: operatorv1.CertManagerContainerSpec{
+				ReadinessProbe: &operatorv1.ProbeTimings{PeriodSeconds: &period, FailureThreshold: &threshold},
+			},
+		},
+	}
+	defaults := *res.WebhookDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe
+
+	webhook := setupDeploy(instance, res.WebhookDeployment, testNS)
+	ready := webhook.Spec.Template.Spec.Containers[0].ReadinessProbe
+	if ready.HTTPGet == nil || ready.HTTPGet.Path != "/healthz" || ready.HTTPGet.Port.IntValue() != res.WebhookHealthPort {
+		t.Errorf("unexpected readiness probe handler %+v", ready.Handler)
+	}
+	if ready.PeriodSeconds != period || ready.FailureThreshold != threshold || ready.TimeoutSeconds != defaults.TimeoutSeconds {
+		t.Errorf("timings not overridden in readiness probe %+v", ready)
+	}
+	if live := webhook.Spec.Template.Spec.Containers[0].LivenessProbe; live.HTTPGet == nil || live.HTTPGet.Path != "/healthz" {
+		t.Errorf("unexpected liveness probe handler %+v", live.Handler)
+	}
+
+	// the controller is only checked for its metrics port
+	controller := setupDeploy(instance, res.ControllerDeployment, testNS)
+	for _, probe := range []*corev1.Probe{controller.Spec.Template.Spec.Containers[0].LivenessProbe, controller.Spec.Template.Spec.Containers[0].ReadinessProbe} {
+		if probe.TCPSocket == nil || probe.TCPSocket.Port.IntValue() != res.ControllerMetricsPort || probe.HTTPGet != nil {
+			t.Errorf("unexpected controller probe handler %+v", probe.Handler)
+		}
+	}
+
+	// the template is left as is for the next reconcile
+	if got := *res.WebhookDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe; got.PeriodSeconds != defaults.PeriodSeconds {
+		t.Errorf("the deployment template was changed to %+v", got)
+	}
+	if reason := deployDiff(setupDeploy(instance, res.WebhookDeployment, testNS), setupDeploy(&operatorv1.CertManagerConfig{}, res.WebhookDeployment, testNS)); reason != "readinessProbe" {
+		t.Errorf("got difference %q for new probe timings, want readinessProbe", reason)
+	}
+}
+
+func TestEqualProbes(t *testing.T) {
+	httpGet := func(path string, scheme corev1.URIScheme) *corev1.Probe {
+		return &corev1.Probe{
+			Handler:       corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt(res.WebhookHealthPort), Scheme: scheme}},
+			PeriodSeconds: 30,
+		}
+	}
+	exec := &corev1.Probe{Handler: corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"pgrep", "webhook"}}}, PeriodSeconds: 30}
+
+	for _, tc := range []struct {
+		name          string
+		first, second *corev1.Probe
+		equal         bool
+	}{
+		{"both nil", nil, nil, true},
+		{"one nil", httpGet("/healthz", ""), nil, false},
+		{"exec and http get", exec, httpGet("/healthz", ""), false},
+		{"http get and exec", httpGet("/healthz", ""), exec, false},
+		{"different paths", httpGet("/healthz", ""), httpGet("/livez", ""), false},
+		{"defaulted scheme", httpGet("/healthz", ""), httpGet("/healthz", corev1.URISchemeHTTP), true},
+		{"different schemes", httpGet("/healthz", corev1.URISchemeHTTPS), httpGet("/healthz", corev1.URISchemeHTTP), false},
+		{"same exec", exec, exec.DeepCopy(), true},
+	} {
+		if got := equalProbes("test", tc.first, tc.second); got != tc.equal {
+			t.Errorf("%s: got equal %v, want %v", tc.name, got, tc.equal)
+		}
+	}
+
+	slower := httpGet("/healthz", "")
+	slower.PeriodSeconds = 60
+	if equalProbes("test", httpGet("/healthz", ""), slower) {
+		t.Error("probes with different periods are equal")
+	}
+}
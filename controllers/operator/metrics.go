@@ -0,0 +1,90 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	reconcilePhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "ibm_cert_manager_operator_reconcile_phase_duration_seconds",
+		Help: "The duration of the phases of the reconcile of the CertManagerConfig.",
+	}, []string{"phase"})
+
+	objectUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ibm_cert_manager_operator_object_updates_total",
+		Help: "The updates of the objects managed by the operator, by kind and by the field that drifted from the wanted state.",
+	}, []string{"kind", "reason"})
+
+	deploymentConflicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ibm_cert_manager_operator_deployment_conflicts_total",
+		Help: "The reconciles that found an operand already deployed under another name or namespace.",
+	}, []string{"deployment"})
+
+	lastSuccessfulReconcile = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ibm_cert_manager_operator_last_successful_reconcile_timestamp_seconds",
+		Help: "The time of the last reconcile of the CertManagerConfig that completed without error.",
+	})
+
+	certificateIssuanceWorking = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ibm_cert_manager_operator_certificate_issuance_working",
+		Help: "Whether the last smoke check issued its test certificate, 1 when it did and 0 when it failed.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcilePhaseDuration, objectUpdates, deploymentConflicts, lastSuccessfulReconcile,
+		certificateIssuanceWorking)
+}
+
+// observePhase records the duration of a reconcile phase started at start
+func observePhase(phase string, start time.Time) {
+	reconcilePhaseDuration.WithLabelValues(phase).Observe(time.Since(start).Seconds())
+}
+
+// recordUpdate counts an update of an object of the kind, with the field
+// that drifted
+func recordUpdate(kind, reason string) {
+	objectUpdates.WithLabelValues(kind, reason).Inc()
+}
+
+// metadataDrift returns which of the labels or annotations of the object
+// drifted from the original
+func metadataDrift(object, original metav1.ObjectMeta) string {
+	if !equality.Semantic.DeepEqual(object.Labels, original.Labels) {
+		return "labels"
+	}
+	if !equality.Semantic.DeepEqual(object.Annotations, original.Annotations) {
+		return "annotations"
+	}
+	return "spec"
+}
+
+// bindingDrift returns whether the role or the subjects of a binding drifted
+func bindingDrift(roleRef, wanted rbacv1.RoleRef) string {
+	if roleRef != wanted {
+		return "roleRef"
+	}
+	return "subjects"
+}
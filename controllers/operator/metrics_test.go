@@ -0,0 +1,98 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestDeployLogicRecordsUpdateReason(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName, UID: "uid"}}
+	existing := setupDeploy(instance, res.ControllerDeployment, testNS)
+	existing.Spec.Template.Spec.Containers[0].Image = "quay.io/old/" + res.ControllerImageName + ":0.1"
+	r := newTestReconciler(t, &existing)
+	kubeclient := kubefake.NewSimpleClientset(&existing)
+
+	updates := testutil.ToFloat64(objectUpdates.WithLabelValues("Deployment", "image"))
+	if err := certManagerDeploy(instance, r.Client, kubeclient, r.Scheme, testNS); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(objectUpdates.WithLabelValues("Deployment", "image")); got != updates+1 {
+		t.Errorf("got %v updates of the deployment image, want %v", got, updates+1)
+	}
+	if testutil.CollectAndCount(reconcilePhaseDuration) == 0 {
+		t.Error("the duration of the deployment phase was not observed")
+	}
+
+	// an up to date deployment is not updated
+	if reason := deployDiff(setupDeploy(instance, res.ControllerDeployment, testNS), setupDeploy(instance, res.ControllerDeployment, testNS)); reason != "" {
+		t.Errorf("got difference %q between identical deployments", reason)
+	}
+}
+
+func TestDeployLogicCountsConflicts(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName, UID: "uid"}}
+	other := setupDeploy(instance, res.ControllerDeployment, "other")
+	r := newTestReconciler(t)
+	kubeclient := kubefake.NewSimpleClientset(&other)
+
+	conflicts := testutil.ToFloat64(deploymentConflicts.WithLabelValues(res.CertManagerControllerName))
+	if err := certManagerDeploy(instance, r.Client, kubeclient, r.Scheme, testNS); err == nil {
+		t.Fatal("no error for cert-manager-controller deployed in another namespace")
+	}
+	if got := testutil.ToFloat64(deploymentConflicts.WithLabelValues(res.CertManagerControllerName)); got != conflicts+1 {
+		t.Errorf("got %v conflicts, want %v", got, conflicts+1)
+	}
+}
+
+func TestCreateRoleBindingSkipsUpToDateBindings(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName, UID: "uid"}}
+	r := newTestReconciler(t)
+	if err := createRoleBinding(instance, r.Scheme, r.Client, testNS); err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Namespace: testNS, Name: res.RoleBindingsToCreate.Items[0].Name}
+	created := &rbacv1.RoleBinding{}
+	if err := r.Client.Get(context.TODO(), key, created); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := testutil.CollectAndCount(objectUpdates)
+	if err := createRoleBinding(instance, r.Scheme, r.Client, testNS); err != nil {
+		t.Fatal(err)
+	}
+	got := &rbacv1.RoleBinding{}
+	if err := r.Client.Get(context.TODO(), key, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ResourceVersion != created.ResourceVersion {
+		t.Errorf("up to date role binding updated, resource version %s, was %s", got.ResourceVersion, created.ResourceVersion)
+	}
+	if count := testutil.CollectAndCount(objectUpdates); count != updates {
+		t.Errorf("got %d update series, was %d", count, updates)
+	}
+}
@@ -0,0 +1,252 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// monitoringEnabled returns whether the metrics of cert-manager-controller
+// are exposed and monitored
+func monitoringEnabled(instance *operatorv1.CertManagerConfig) bool {
+	return instance.Spec.Monitoring != nil && instance.Spec.Monitoring.Enabled
+}
+
+// reconcileMonitoring creates the metrics Services of cert-manager-controller
+// and of the operator, and their ServiceMonitors and the PrometheusRule when
+// the Prometheus operator is installed, while monitoring is enabled. It
+// deletes them otherwise.
+func (r *CertManagerReconciler) reconcileMonitoring(instance *operatorv1.CertManagerConfig) error {
+	if !monitoringEnabled(instance) {
+		// deleting them is a no-op when the Prometheus operator is not installed
+		if err := r.deleteMonitor("ServiceMonitor", res.ServiceMonitorName); err != nil {
+			return err
+		}
+		if err := r.deleteMonitor("ServiceMonitor", res.OperatorServiceMonitorName); err != nil {
+			return err
+		}
+		if err := r.deleteMonitor("PrometheusRule", res.PrometheusRuleName); err != nil {
+			return err
+		}
+		if err := r.deleteService(res.OperatorMetricsServiceName); err != nil {
+			return err
+		}
+		return r.deleteService(res.ControllerMetricsServiceName)
+	}
+
+	if err := r.applyService(instance, metricsService(res.ControllerMetricsServiceName, r.NS, res.ControllerLabelMap,
+		res.ControllerMetricsPortName, res.ControllerMetricsPort)); err != nil {
+		return err
+	}
+	// the alerts of the PrometheusRule are computed from the metrics of the
+	// operator
+	if err := r.applyService(instance, metricsService(res.OperatorMetricsServiceName, r.NS, res.OperatorLabelMap,
+		res.OperatorMetricsPortName, res.OperatorMetricsPort)); err != nil {
+		return err
+	}
+
+	prometheusOperator, err := apiAvailable(r.Kubeclient, res.MonitoringGroupVersion)
+	if err != nil {
+		return err
+	}
+	if !prometheusOperator {
+		logd.V(2).Info("The monitoring.coreos.com API is not installed, skipping the ServiceMonitor and the PrometheusRule")
+		return nil
+	}
+	if err := r.applyMonitor(instance, serviceMonitor(instance, res.ServiceMonitorName, r.NS, res.ControllerLabelMap,
+		res.ControllerMetricsPortName)); err != nil {
+		return err
+	}
+	if err := r.applyMonitor(instance, serviceMonitor(instance, res.OperatorServiceMonitorName, r.NS, res.OperatorLabelMap,
+		res.OperatorMetricsPortName)); err != nil {
+		return err
+	}
+	return r.applyMonitor(instance, prometheusRule(instance, r.NS))
+}
+
+// metricsService returns the Service in front of the metrics port of the
+// selected pods
+func metricsService(name, namespace string, selector map[string]string, portName string, port int32) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: selector},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports: []corev1.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				TargetPort: intstr.FromString(portName),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// serviceMonitor returns the ServiceMonitor scraping the metrics Service
+// with the labels
+func serviceMonitor(instance *operatorv1.CertManagerConfig, name, namespace string, labels map[string]string,
+	portName string) *unstructured.Unstructured {
+	interval := res.DefaultScrapeInterval
+	if instance.Spec.Monitoring.Interval != "" {
+		interval = instance.Spec.Monitoring.Interval
+	}
+	monitor := newMonitor("ServiceMonitor", name, namespace, instance.Spec.Monitoring.Labels)
+	monitor.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": stringMap(labels),
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{namespace},
+		},
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"port":     portName,
+				"path":     "/metrics",
+				"interval": interval,
+			},
+		},
+	}
+	return monitor
+}
+
+// prometheusRule returns the PrometheusRule alerting on expiring
+// certificates, failed issuance and issuers not ready. The alerts are
+// computed from the metrics of the operator itself, which are the same
+// whatever the version of cert-manager.
+func prometheusRule(instance *operatorv1.CertManagerConfig, namespace string) *unstructured.Unstructured {
+	threshold := res.DefaultCertificateExpiryThreshold
+	if instance.Spec.Monitoring.CertificateExpiryThreshold != nil {
+		threshold = instance.Spec.Monitoring.CertificateExpiryThreshold.Duration
+	}
+	rule := newMonitor("PrometheusRule", res.PrometheusRuleName, namespace, instance.Spec.Monitoring.Labels)
+	rule.Object["spec"] = map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name": "cert-manager",
+				"rules": []interface{}{
+					alert("CertManagerCertificateExpiringSoon",
+						fmt.Sprintf("(ibm_cert_manager_certificate_expiration_timestamp_seconds - time()) < %d", int64(threshold/time.Second)),
+						"1h",
+						"Certificate {{ $labels.namespace }}/{{ $labels.name }} expires soon",
+						"The certificate expires in {{ $value | humanizeDuration }} and was not renewed."),
+					alert("CertManagerCertificateIssuanceFailed",
+						`ibm_cert_manager_certificate_ready_status{condition="False"} == 1`,
+						"15m",
+						"Certificate {{ $labels.namespace }}/{{ $labels.name }} cannot be issued",
+						"The certificate has not been ready for 15 minutes. Check the status of its CertificateRequests."),
+					alert("CertManagerIssuerNotReady",
+						`ibm_cert_manager_issuer_ready_status{condition="True"} == 0`,
+						"10m",
+						"{{ $labels.kind }} {{ $labels.namespace }}/{{ $labels.name }} is not ready",
+						"The issuer has not been ready for 10 minutes, its certificates cannot be issued or renewed."),
+				},
+			},
+		},
+	}
+	return rule
+}
+
+func alert(name, expr, forDuration, summary, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"alert":  name,
+		"expr":   expr,
+		"for":    forDuration,
+		"labels": map[string]interface{}{"severity": "warning"},
+		"annotations": map[string]interface{}{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+}
+
+func newMonitor(kind, name, namespace string, labels map[string]string) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(res.MonitoringGroupVersion.WithKind(kind))
+	monitor.SetName(name)
+	monitor.SetNamespace(namespace)
+	monitor.SetLabels(labels)
+	return monitor
+}
+
+// stringMap converts labels to the map type of unstructured objects
+func stringMap(labels map[string]string) map[string]interface{} {
+	m := map[string]interface{}{}
+	for k, v := range labels {
+		m[k] = v
+	}
+	return m
+}
+
+// applyMonitor creates the ServiceMonitor or PrometheusRule, or updates its
+// labels and spec when they changed
+func (r *CertManagerReconciler) applyMonitor(instance *operatorv1.CertManagerConfig, monitor *unstructured.Unstructured) error {
+	if err := controllerutil.SetControllerReference(instance, monitor, r.Scheme); err != nil {
+		return err
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(monitor.GroupVersionKind())
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: monitor.GetNamespace(), Name: monitor.GetName()}, existing)
+	if errors.IsNotFound(err) {
+		logd.Info("Creating "+monitor.GetKind(), "name", monitor.GetName())
+		return r.Client.Create(context.TODO(), monitor)
+	} else if err != nil {
+		return err
+	}
+	if isSubset(monitor.GetLabels(), existing.GetLabels()) && equality.Semantic.DeepEqual(existing.Object["spec"], monitor.Object["spec"]) {
+		return nil
+	}
+	reason := "spec"
+	if !isSubset(monitor.GetLabels(), existing.GetLabels()) {
+		reason = "labels"
+	}
+	existing.SetLabels(mergeLabels(existing.GetLabels(), monitor.GetLabels()))
+	existing.Object["spec"] = monitor.Object["spec"]
+	logd.Info("Updating "+monitor.GetKind(), "name", monitor.GetName(), "reason", reason)
+	if err := r.Client.Update(context.TODO(), existing); err != nil {
+		return err
+	}
+	recordUpdate(monitor.GetKind(), reason)
+	return nil
+}
+
+// deleteMonitor deletes a ServiceMonitor or PrometheusRule of the operator
+// namespace if it exists
+func (r *CertManagerReconciler) deleteMonitor(kind, name string) error {
+	monitor := newMonitor(kind, name, r.NS, nil)
+	if err := r.Client.Delete(context.TODO(), monitor); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	logd.Info("Deleted "+kind, "name", name)
+	return nil
+}
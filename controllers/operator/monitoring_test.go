@@ -0,0 +1,174 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func newMonitoringReconciler(t *testing.T, prometheusOperator bool, instance *operatorv1.CertManagerConfig) *CertManagerReconciler {
+	t.Helper()
+	r := newTestReconciler(t, instance)
+	kubeclient := kubefake.NewSimpleClientset()
+	if prometheusOperator {
+		kubeclient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: res.MonitoringGroupVersion.String(), APIResources: []metav1.APIResource{
+				{Name: "servicemonitors", Kind: "ServiceMonitor", Namespaced: true},
+				{Name: "prometheusrules", Kind: "PrometheusRule", Namespaced: true},
+			}},
+		}
+	}
+	r.Kubeclient = kubeclient
+	return r
+}
+
+func getMonitor(r *CertManagerReconciler, kind, name string) (*unstructured.Unstructured, error) {
+	monitor := newMonitor(kind, "", "", nil)
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: name}, monitor)
+	return monitor, err
+}
+
+func TestReconcileMonitoring(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Monitoring: &operatorv1.MonitoringSpec{
+				Enabled:                    true,
+				Labels:                     map[string]string{"prometheus": "k8s"},
+				CertificateExpiryThreshold: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			},
+		},
+	}
+	r := newMonitoringReconciler(t, true, instance)
+
+	deploy := setupDeploy(instance, res.ControllerDeployment, testNS)
+	ports := deploy.Spec.Template.Spec.Containers[0].Ports
+	if len(ports) != 1 || ports[0].ContainerPort != res.ControllerMetricsPort {
+		t.Errorf("unexpected controller ports %+v", ports)
+	}
+
+	if err := r.reconcileMonitoring(instance); err != nil {
+		t.Fatal(err)
+	}
+	serviceKey := types.NamespacedName{Namespace: testNS, Name: res.ControllerMetricsServiceName}
+	if err := r.Client.Get(context.TODO(), serviceKey, &corev1.Service{}); err != nil {
+		t.Fatal(err)
+	}
+	operatorServiceKey := types.NamespacedName{Namespace: testNS, Name: res.OperatorMetricsServiceName}
+	operatorService := &corev1.Service{}
+	if err := r.Client.Get(context.TODO(), operatorServiceKey, operatorService); err != nil {
+		t.Fatal(err)
+	}
+	if ports := operatorService.Spec.Ports; len(ports) != 1 || ports[0].Port != res.OperatorMetricsPort {
+		t.Errorf("unexpected operator metrics ports %+v", ports)
+	}
+	operatorMonitor, err := getMonitor(r, "ServiceMonitor", res.OperatorServiceMonitorName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selector, _, _ := unstructured.NestedStringMap(operatorMonitor.Object, "spec", "selector", "matchLabels"); selector["name"] != "ibm-cert-manager-operator" {
+		t.Errorf("operator ServiceMonitor selects %v", selector)
+	}
+	monitor, err := getMonitor(r, "ServiceMonitor", res.ServiceMonitorName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if monitor.GetLabels()["prometheus"] != "k8s" {
+		t.Errorf("ServiceMonitor labels %v miss the Prometheus selector", monitor.GetLabels())
+	}
+	endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "endpoints")
+	if len(endpoints) != 1 || endpoints[0].(map[string]interface{})["interval"] != res.DefaultScrapeInterval {
+		t.Errorf("unexpected ServiceMonitor endpoints %v", endpoints)
+	}
+	rule, err := getMonitor(r, "PrometheusRule", res.PrometheusRuleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+	if len(rules) != 3 {
+		t.Fatalf("got %d alerts, want 3", len(rules))
+	}
+	if expr := rules[0].(map[string]interface{})["expr"].(string); !strings.HasSuffix(expr, "< 604800") {
+		t.Errorf("expiry alert %q does not use the threshold", expr)
+	}
+	// the alerts do not depend on the metrics of a given cert-manager version
+	for _, rule := range rules {
+		if expr := rule.(map[string]interface{})["expr"].(string); !strings.Contains(expr, "ibm_cert_manager_") {
+			t.Errorf("alert %q is not computed from the metrics of the operator", expr)
+		}
+	}
+
+	// applying again leaves them alone
+	if err := r.reconcileMonitoring(instance); err != nil {
+		t.Fatal(err)
+	}
+
+	instance.Spec.Monitoring.Enabled = false
+	if err := r.reconcileMonitoring(instance); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []types.NamespacedName{serviceKey, operatorServiceKey} {
+		if err := r.Client.Get(context.TODO(), key, &corev1.Service{}); !errors.IsNotFound(err) {
+			t.Errorf("metrics service %s not deleted once monitoring is disabled: %v", key.Name, err)
+		}
+	}
+	for name, kind := range map[string]string{
+		res.ServiceMonitorName:         "ServiceMonitor",
+		res.OperatorServiceMonitorName: "ServiceMonitor",
+		res.PrometheusRuleName:         "PrometheusRule",
+	} {
+		if _, err := getMonitor(r, kind, name); !errors.IsNotFound(err) {
+			t.Errorf("%s %s not deleted once monitoring is disabled: %v", kind, name, err)
+		}
+	}
+	deploy = setupDeploy(instance, res.ControllerDeployment, testNS)
+	if ports := deploy.Spec.Template.Spec.Containers[0].Ports; len(ports) != 0 {
+		t.Errorf("controller ports %+v once monitoring is disabled", ports)
+	}
+}
+
+func TestReconcileMonitoringWithoutPrometheusOperator(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec:       operatorv1.CertManagerConfigSpec{Monitoring: &operatorv1.MonitoringSpec{Enabled: true}},
+	}
+	r := newMonitoringReconciler(t, false, instance)
+	if err := r.reconcileMonitoring(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: res.ControllerMetricsServiceName}, &corev1.Service{}); err != nil {
+		t.Errorf("no metrics service without the Prometheus operator: %v", err)
+	}
+	if _, err := getMonitor(r, "ServiceMonitor", res.ServiceMonitorName); !errors.IsNotFound(err) {
+		t.Errorf("ServiceMonitor created without the Prometheus operator: %v", err)
+	}
+}
@@ -153,6 +153,7 @@ func webhooks(instance *operatorv1.CertManagerConfig, scheme *runtime.Scheme, cl
 			if err != nil {
 				return err
 			}
+			recordUpdate("MutatingWebhookConfiguration", metadataDrift(mutating.ObjectMeta, originalmutating.ObjectMeta))
 		}
 	}
 
@@ -197,6 +198,7 @@ func webhooks(instance *operatorv1.CertManagerConfig, scheme *runtime.Scheme, cl
 			if err != nil {
 				return err
 			}
+			recordUpdate("ValidatingWebhookConfiguration", metadataDrift(validating.ObjectMeta, originalValidating.ObjectMeta))
 		}
 	}
 
@@ -265,6 +267,7 @@ func service(instance *operatorv1.CertManagerConfig, scheme *runtime.Scheme, cli
 		if err != nil {
 			return err
 		}
+		recordUpdate("Service", metadataDrift(svc.ObjectMeta, originalService.ObjectMeta))
 	}
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pkg/errors"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionclientsetv1beta1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -92,6 +93,7 @@ func createRole(instance *operatorv1.CertManagerConfig, scheme *runtime.Scheme,
 				if err != nil {
 					return err
 				}
+				recordUpdate("Role", "rules")
 			}
 		}
 	}
@@ -124,6 +126,7 @@ func createClusterRole(instance *operatorv1.CertManagerConfig, scheme *runtime.S
 				if err != nil {
 					return err
 				}
+				recordUpdate("ClusterRole", "rules")
 			}
 		}
 	}
@@ -163,6 +166,7 @@ func createClusterRoleBinding(instance *operatorv1.CertManagerConfig, scheme *ru
 				if err != nil {
 					return err
 				}
+				recordUpdate("ClusterRoleBinding", bindingDrift(oldClusterRoleBinding.RoleRef, b.RoleRef))
 			}
 		}
 	}
@@ -204,10 +208,7 @@ func createRoleBinding(instance *operatorv1.CertManagerConfig, scheme *runtime.S
 				if err != nil {
 					return err
 				}
-			}
-			err := client.Update(context.Background(), roleBinding)
-			if err != nil {
-				return err
+				recordUpdate("RoleBinding", bindingDrift(oldRolebinding.RoleRef, b.RoleRef))
 			}
 		}
 	}
@@ -293,6 +294,35 @@ func checkCrds(instance *operatorv1.CertManagerConfig, scheme *runtime.Scheme, c
 	return nil
 }
 
+// Labels the cert-manager CRDs so that their deletion is checked by the CRD
+// deletion webhook. CRDs that are not installed yet are skipped.
+func protectCRDs(client client.Client) error {
+	for _, name := range res.ProtectedCRDs {
+		crd := &apiextensionv1.CustomResourceDefinition{}
+		err := client.Get(context.Background(), types.NamespacedName{Name: name}, crd)
+		if err != nil {
+			if apiErrors.IsNotFound(err) {
+				logd.V(2).Info("CRD not found, skip protecting it", "name", name)
+				continue
+			}
+			return err
+		}
+		if crd.Labels[res.CRDProtectionLabel] == "true" {
+			continue
+		}
+		if crd.Labels == nil {
+			crd.Labels = make(map[string]string)
+		}
+		crd.Labels[res.CRDProtectionLabel] = "true"
+		logd.Info("Adding deletion protection label to CRD " + name)
+		if err := client.Update(context.Background(), crd); err != nil {
+			return err
+		}
+		recordUpdate("CustomResourceDefinition", "protectionLabel")
+	}
+	return nil
+}
+
 // Removes the clusterrole and clusterrolebinding created by this operator
 func removeRoles(client client.Client) error {
 	// Delete the clusterrolebinding
@@ -0,0 +1,156 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// readinessCheckTimeout is how long a readiness check waits for the caches or
+// for cert-manager-webhook
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadyzChecks returns the checks the operator is ready with, by name. Each
+// check is listed under /readyz?verbose and served under /readyz/<name>.
+// elected is closed once the operator holds the leader lease, see
+// manager.Manager.Elected.
+func (r *CertManagerReconciler) ReadyzChecks(informers cache.Informers, elected <-chan struct{}) map[string]healthz.Checker {
+	return map[string]healthz.Checker{
+		"cache-sync": cacheSyncCheck(informers),
+		"reconciled": r.reconciledCheck(elected),
+		"webhook":    r.webhookCheck(elected),
+		"revocation": r.revocationCheck(elected),
+	}
+}
+
+// leading tells whether the operator holds the leader lease. Only the leader
+// reconciles the CertManagerConfig, so the other replicas are ready without
+// waiting for it.
+func leading(elected <-chan struct{}) bool {
+	select {
+	case <-elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// instanceExists tells whether the CertManagerConfig exists. It is created
+// once the operator is installed, by OLM and ODLM only after the operator is
+// ready, so the checks on it pass until then.
+func (r *CertManagerReconciler) instanceExists(ctx context.Context, instance *operatorv1.CertManagerConfig) (bool, error) {
+	err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, instance)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot read the CertManagerConfig %s: %v", res.CertManagerInstanceName, err)
+	}
+	return true, nil
+}
+
+// cacheSyncCheck passes once the informers the controllers read from have
+// synced
+func cacheSyncCheck(informers cache.Informers) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), readinessCheckTimeout)
+		defer cancel()
+		if !informers.WaitForCacheSync(ctx) {
+			return fmt.Errorf("the caches have not synced")
+		}
+		return nil
+	}
+}
+
+// reconciledCheck passes once the CertManagerConfig has been reconciled
+// successfully, while it does not exist, or when the operator is not the
+// leader
+func (r *CertManagerReconciler) reconciledCheck(elected <-chan struct{}) healthz.Checker {
+	return func(req *http.Request) error {
+		if r.reconciled.Load() || !leading(elected) {
+			return nil
+		}
+		exists, err := r.instanceExists(req.Context(), &operatorv1.CertManagerConfig{})
+		if err != nil || !exists {
+			return err
+		}
+		return fmt.Errorf("the CertManagerConfig %s has not been reconciled successfully yet", res.CertManagerInstanceName)
+	}
+}
+
+// webhookCheck passes when the service of cert-manager-webhook accepts
+// connections, when the webhook is not deployed, or when the operator is not
+// the leader
+func (r *CertManagerReconciler) webhookCheck(elected <-chan struct{}) healthz.Checker {
+	return func(req *http.Request) error {
+		if !leading(elected) {
+			return nil
+		}
+		instance := &operatorv1.CertManagerConfig{}
+		exists, err := r.instanceExists(req.Context(), instance)
+		if err != nil || !exists || !instance.Spec.Webhook {
+			return err
+		}
+		return r.dialWebhook(req.Context())
+	}
+}
+
+// revocationCheck fails on the replicas that are not the leader while the
+// CRLs or the OCSP responder are enabled. The revocation server only serves
+// on the leader, so the other replicas are kept out of its service.
+func (r *CertManagerReconciler) revocationCheck(elected <-chan struct{}) healthz.Checker {
+	return func(req *http.Request) error {
+		if leading(elected) {
+			return nil
+		}
+		instance := &operatorv1.CertManagerConfig{}
+		exists, err := r.instanceExists(req.Context(), instance)
+		if err != nil || !exists || (!crlEnabled(instance) && !ocspEnabled(instance)) {
+			return err
+		}
+		return fmt.Errorf("the CRLs and the OCSP responder are only served by the leader")
+	}
+}
+
+// dialWebhook connects to the service of cert-manager-webhook
+func (r *CertManagerReconciler) dialWebhook(ctx context.Context) error {
+
+	dial := r.dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	address := fmt.Sprintf("%s.%s.svc:%d", res.WebhookSvc.Name, r.NS, res.WebhookSvc.Spec.Ports[0].Port)
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("cert-manager-webhook is not reachable at %s: %v", address, err)
+	}
+	return conn.Close()
+}
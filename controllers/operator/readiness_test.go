@@ -0,0 +1,184 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestReadyzChecks(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec:       operatorv1.CertManagerConfigSpec{Webhook: true},
+	}
+	r := newTestReconciler(t, instance)
+	var dialed string
+	webhookUp := false
+	r.dial = func(_ context.Context, _, address string) (net.Conn, error) {
+		dialed = address
+		if !webhookUp {
+			return nil, fmt.Errorf("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+	synced := false
+	elected := make(chan struct{})
+	close(elected)
+	handler := &healthz.Handler{Checks: r.ReadyzChecks(&informertest.FakeInformers{Synced: &synced}, elected)}
+
+	readyz := func() (int, string) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?verbose", nil))
+		return resp.Code, resp.Body.String()
+	}
+
+	code, body := readyz()
+	if code != http.StatusInternalServerError {
+		t.Errorf("got status %d before the caches synced, want %d", code, http.StatusInternalServerError)
+	}
+	for _, want := range []string{"[-]cache-sync failed", "[-]reconciled failed", "[-]webhook failed"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q not in the verbose output:\n%s", want, body)
+		}
+	}
+	if dialed != "cert-manager-webhook."+testNS+".svc:443" {
+		t.Errorf("dialed %q for the webhook", dialed)
+	}
+
+	synced = true
+	webhookUp = true
+	r.reconciled.Store(true)
+	code, body = readyz()
+	if code != http.StatusOK {
+		t.Errorf("got status %d once ready, want %d:\n%s", code, http.StatusOK, body)
+	}
+	for _, want := range []string{"[+]cache-sync ok", "[+]reconciled ok", "[+]webhook ok"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q not in the verbose output:\n%s", want, body)
+		}
+	}
+
+	// the webhook is not checked when it is not deployed
+	webhookUp = false
+	instance.Spec.Webhook = false
+	if err := r.Client.Update(context.TODO(), instance); err != nil {
+		t.Fatal(err)
+	}
+	if code, body = readyz(); code != http.StatusOK {
+		t.Errorf("got status %d without the webhook, want %d:\n%s", code, http.StatusOK, body)
+	}
+}
+
+func TestReadyzChecksWithoutInstance(t *testing.T) {
+	r := newTestReconciler(t)
+	r.dial = func(_ context.Context, _, _ string) (net.Conn, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+	synced := true
+	elected := make(chan struct{})
+	close(elected)
+	handler := &healthz.Handler{Checks: r.ReadyzChecks(&informertest.FakeInformers{Synced: &synced}, elected)}
+
+	// OLM creates the CertManagerConfig only once the operator is ready
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?verbose", nil))
+	if resp.Code != http.StatusOK {
+		t.Errorf("got status %d without a CertManagerConfig, want %d:\n%s", resp.Code, http.StatusOK, resp.Body.String())
+	}
+}
+
+func TestReadyzChecksNotLeader(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec:       operatorv1.CertManagerConfigSpec{Webhook: true},
+	}
+	r := newTestReconciler(t, instance)
+	r.dial = func(_ context.Context, _, _ string) (net.Conn, error) {
+		return nil, fmt.Errorf("connection refused")
+	}
+	synced := true
+	elected := make(chan struct{})
+	handler := &healthz.Handler{Checks: r.ReadyzChecks(&informertest.FakeInformers{Synced: &synced}, elected)}
+
+	readyz := func() int {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+		return resp.Code
+	}
+	if code := readyz(); code != http.StatusOK {
+		t.Errorf("got status %d while not the leader, want %d", code, http.StatusOK)
+	}
+	close(elected)
+	if code := readyz(); code != http.StatusInternalServerError {
+		t.Errorf("got status %d once the leader, want %d", code, http.StatusInternalServerError)
+	}
+}
+
+func TestReadyzChecksRevocationNotLeader(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Revocation: &operatorv1.RevocationSpec{CRL: &operatorv1.CRLSpec{Enabled: true}},
+		},
+	}
+	r := newTestReconciler(t, instance)
+	synced := true
+	elected := make(chan struct{})
+	handler := &healthz.Handler{Checks: r.ReadyzChecks(&informertest.FakeInformers{Synced: &synced}, elected)}
+
+	readyz := func() (int, string) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?verbose", nil))
+		return resp.Code, resp.Body.String()
+	}
+	code, body := readyz()
+	if code != http.StatusInternalServerError || !strings.Contains(body, "[-]revocation failed") {
+		t.Errorf("got status %d with the CRLs enabled while not the leader, want %d:\n%s", code, http.StatusInternalServerError, body)
+	}
+
+	r.reconciled.Store(true)
+	close(elected)
+	if code, body = readyz(); code != http.StatusOK {
+		t.Errorf("got status %d once the leader, want %d:\n%s", code, http.StatusOK, body)
+	}
+
+	// the other replicas are ready again once revocation is disabled
+	elected = make(chan struct{})
+	handler.Checks = r.ReadyzChecks(&informertest.FakeInformers{Synced: &synced}, elected)
+	instance.Spec.Revocation = nil
+	if err := r.Client.Update(context.TODO(), instance); err != nil {
+		t.Fatal(err)
+	}
+	if code, body = readyz(); code != http.StatusOK {
+		t.Errorf("got status %d with revocation disabled while not the leader, want %d:\n%s", code, http.StatusOK, body)
+	}
+}
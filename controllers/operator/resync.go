@@ -0,0 +1,103 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// DefaultResyncPeriod is how often the CertManagerConfig is reconciled again
+// when no resync period is set. The resync heals the objects the operator
+// does not watch, and looks for RHACM since MultiClusterHubs are not watched.
+const DefaultResyncPeriod = 10 * time.Minute
+
+// resyncPeriod returns how long to wait before reconciling the
+// CertManagerConfig again once it is reconciled
+func (r *CertManagerReconciler) resyncPeriod() time.Duration {
+	if r.ResyncPeriod <= 0 {
+		return DefaultResyncPeriod
+	}
+	return r.ResyncPeriod
+}
+
+// operandObjectPredicate passes the changes and the deletions of the objects
+// created by the operator. Their creations are the operator's own doing.
+var operandObjectPredicate = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// operandObject maps the objects the operator creates without owning them
+// through the CertManagerConfig, or without watching them as owned, to the
+// CertManagerConfig
+func (r *CertManagerReconciler) operandObject(obj client.Object) []reconcile.Request {
+	var created bool
+	switch obj.(type) {
+	case *rbacv1.Role:
+		for _, role := range res.RolesToCreate.Items {
+			created = created || obj.GetNamespace() == r.NS && obj.GetName() == role.Name
+		}
+	case *rbacv1.RoleBinding:
+		for _, binding := range res.RoleBindingsToCreate.Items {
+			created = created || obj.GetNamespace() == r.NS && obj.GetName() == binding.Name
+		}
+	case *apiextensionv1.CustomResourceDefinition:
+		created = containsString(res.ProtectedCRDs, obj.GetName())
+	case *corev1.Secret:
+		created = obj.GetNamespace() == r.NS && obj.GetName() == res.WebhookServingSecret
+	}
+	if !created {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}}
+}
+
+// labelServingSecret labels the serving secret of cert-manager-webhook for
+// the cache of the operator, so that it is watched
+func (r *CertManagerReconciler) labelServingSecret() error {
+	// the secret is not in the cache until it is labelled
+	secret := &corev1.Secret{}
+	err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.WebhookServingSecret}, secret)
+	if errors.IsNotFound(err) {
+		// cert-manager-webhook has not created it yet
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; ok {
+		return nil
+	}
+	secret.Labels = mergeLabels(secret.Labels, map[string]string{res.SecretWatchLabel: ""})
+	if err := r.Client.Update(context.TODO(), secret); err != nil {
+		return err
+	}
+	recordUpdate("Secret", "watchLabel")
+	return nil
+}
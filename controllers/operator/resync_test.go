@@ -0,0 +1,97 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestOperandObject(t *testing.T) {
+	r := newTestReconciler(t)
+	for _, tc := range []struct {
+		name     string
+		obj      client.Object
+		enqueued bool
+	}{
+		{"role", &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: res.WebhookRole.Name, Namespace: testNS}}, true},
+		{"role in another namespace", &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: res.WebhookRole.Name, Namespace: "other"}}, false},
+		{"other role", &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNS}}, false},
+		{"role binding", &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: res.ControllerRoleBinding.Name, Namespace: testNS}}, true},
+		{"protected CRD", &apiextensionv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: res.ProtectedCRDs[0]}}, true},
+		{"other CRD", &apiextensionv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "others.example.com"}}, false},
+		{"webhook serving secret", &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: res.WebhookServingSecret, Namespace: testNS}}, true},
+		{"other secret", &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNS}}, false},
+	} {
+		requests := r.operandObject(tc.obj)
+		if enqueued := len(requests) == 1 && requests[0].Name == res.CertManagerInstanceName; enqueued != tc.enqueued {
+			t.Errorf("%s: got requests %v, want enqueued %v", tc.name, requests, tc.enqueued)
+		}
+	}
+
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: res.WebhookRole.Name, Namespace: testNS}}
+	if operandObjectPredicate.Create(event.CreateEvent{Object: role}) {
+		t.Error("the creation of a role enqueued the CertManagerConfig")
+	}
+	if !operandObjectPredicate.Delete(event.DeleteEvent{Object: role}) || !operandObjectPredicate.Update(event.UpdateEvent{ObjectOld: role, ObjectNew: role}) {
+		t.Error("the deletion or the change of a role did not enqueue the CertManagerConfig")
+	}
+}
+
+func TestLabelServingSecret(t *testing.T) {
+	r := newTestReconciler(t)
+	if err := r.labelServingSecret(); err != nil {
+		t.Fatalf("error before the secret is created: %v", err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.WebhookServingSecret, Namespace: testNS, Labels: map[string]string{"app": "webhook"}},
+	}
+	r = newTestReconciler(t, secret)
+	if err := r.labelServingSecret(); err != nil {
+		t.Fatal(err)
+	}
+	got := &corev1.Secret{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: res.WebhookServingSecret, Namespace: testNS}, got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Labels[res.SecretWatchLabel]; !ok || got.Labels["app"] != "webhook" {
+		t.Errorf("unexpected labels %v", got.Labels)
+	}
+}
+
+func TestResyncPeriod(t *testing.T) {
+	r := newTestReconciler(t)
+	if got := r.resyncPeriod(); got != DefaultResyncPeriod {
+		t.Errorf("got resync period %v, want %v", got, DefaultResyncPeriod)
+	}
+	r.ResyncPeriod = 2 * time.Minute
+	if got := r.resyncPeriod(); got != 2*time.Minute {
+		t.Errorf("got resync period %v, want 2m", got)
+	}
+}
@@ -0,0 +1,139 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// crlEnabled returns whether the operator publishes the CRLs of the CA issuers
+func crlEnabled(instance *operatorv1.CertManagerConfig) bool {
+	spec := instance.Spec.Revocation
+	return spec != nil && spec.CRL != nil && spec.CRL.Enabled
+}
+
+// ocspEnabled returns whether the operator runs the OCSP responder
+func ocspEnabled(instance *operatorv1.CertManagerConfig) bool {
+	spec := instance.Spec.Revocation
+	return spec != nil && spec.OCSP != nil && spec.OCSP.Enabled
+}
+
+// ocspAnswersFor returns whether the OCSP responder answers for the Issuer of
+// the namespace of the operator
+func ocspAnswersFor(instance *operatorv1.CertManagerConfig, issuerName string) bool {
+	if !ocspEnabled(instance) {
+		return false
+	}
+	ref := instance.Spec.Revocation.OCSP.IssuerRef
+	if ref == nil {
+		return issuerName == res.CSCAIssuerName
+	}
+	return ref.Name == issuerName && (ref.Kind == "" || ref.Kind == certmanagerv1.IssuerKind) &&
+		(ref.Group == "" || ref.Group == certmanagerv1.SchemeGroupVersion.Group)
+}
+
+// ocspURL returns the URL of the OCSP responder behind the revocation Service
+func ocspURL(namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc%s", res.RevocationServiceName, namespace, res.OCSPPath)
+}
+
+// crlURL returns the URL of the CRL of an Issuer behind the revocation Service
+func crlURL(namespace, issuerName string) string {
+	return fmt.Sprintf("http://%s.%s.svc%s", res.RevocationServiceName, namespace, res.CRLPath(namespace, issuerName))
+}
+
+// reconcileRevocationService creates the Service in front of the revocation
+// server of the operator while the CRLs or the OCSP responder are enabled,
+// and deletes it otherwise
+func (r *CertManagerReconciler) reconcileRevocationService(instance *operatorv1.CertManagerConfig) error {
+	if !crlEnabled(instance) && !ocspEnabled(instance) {
+		return r.deleteService(res.RevocationServiceName)
+	}
+	return r.applyService(instance, &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: res.RevocationServiceName, Namespace: r.NS},
+		Spec: corev1.ServiceSpec{
+			Selector: res.OperatorLabelMap,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(res.RevocationServerPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	})
+}
+
+// applyService creates the Service owned by the instance, or updates its
+// labels, selector and ports when they changed
+func (r *CertManagerReconciler) applyService(instance *operatorv1.CertManagerConfig, service *corev1.Service) error {
+	if err := controllerutil.SetControllerReference(instance, service, r.Scheme); err != nil {
+		return err
+	}
+	existing := &corev1.Service{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, existing)
+	if errors.IsNotFound(err) {
+		logd.Info("Creating service", "name", service.Name)
+		return r.Client.Create(context.TODO(), service)
+	} else if err != nil {
+		return err
+	}
+	if isSubset(service.Labels, existing.Labels) && reflect.DeepEqual(existing.Spec.Selector, service.Spec.Selector) &&
+		reflect.DeepEqual(existing.Spec.Ports, service.Spec.Ports) {
+		return nil
+	}
+	reason := "spec"
+	if !isSubset(service.Labels, existing.Labels) {
+		reason = "labels"
+	}
+	// the cluster IP and the defaulted fields of the existing service are kept
+	existing.Labels = mergeLabels(existing.Labels, service.Labels)
+	existing.Spec.Selector = service.Spec.Selector
+	existing.Spec.Ports = service.Spec.Ports
+	logd.Info("Updating service", "name", service.Name, "reason", reason)
+	if err := r.Client.Update(context.TODO(), existing); err != nil {
+		return err
+	}
+	recordUpdate("Service", reason)
+	return nil
+}
+
+// deleteService deletes a Service of the operator namespace if it exists
+func (r *CertManagerReconciler) deleteService(name string) error {
+	existing := &corev1.Service{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: name}, existing)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logd.Info("Deleting service", "name", name)
+	return client.IgnoreNotFound(r.Client.Delete(context.TODO(), existing))
+}
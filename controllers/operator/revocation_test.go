@@ -0,0 +1,118 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func TestReconcileRevocationService(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Revocation: &operatorv1.RevocationSpec{CRL: &operatorv1.CRLSpec{Enabled: true}},
+		},
+	}
+	r := newTestReconciler(t, instance)
+
+	ctx := context.TODO()
+	key := types.NamespacedName{Namespace: testNS, Name: res.RevocationServiceName}
+	if err := r.reconcileRevocationService(instance); err != nil {
+		t.Fatal(err)
+	}
+	service := &corev1.Service{}
+	if err := r.Client.Get(ctx, key, service); err != nil {
+		t.Fatal(err)
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].TargetPort.IntValue() != res.RevocationServerPort {
+		t.Errorf("unexpected ports %+v", service.Spec.Ports)
+	}
+
+	issuer := csCAIssuer(instance, testNS)
+	want := "http://" + res.RevocationServiceName + "." + testNS + ".svc/crl/issuers/" + testNS + "/" + res.CSCAIssuerName + ".crl"
+	if got := issuer.Spec.CA.CRLDistributionPoints; len(got) != 1 || got[0] != want {
+		t.Errorf("got CRL distribution points %v, want %s", got, want)
+	}
+	if usages := csCACertificate(instance, testNS).Spec.Usages; !containsUsage(usages, certmanagerv1.UsageCRLSign) {
+		t.Errorf("CS CA cannot sign CRLs with usages %v", usages)
+	}
+
+	usages := csCACertificate(instance, testNS).Spec.Usages
+
+	instance.Spec.Revocation = nil
+	if got := csCACertificate(instance, testNS).Spec.Usages; !reflect.DeepEqual(got, usages) {
+		t.Errorf("got CS CA usages %v once the CRLs are disabled, want %v", got, usages)
+	}
+	if err := r.reconcileRevocationService(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, key, &corev1.Service{}); !errors.IsNotFound(err) {
+		t.Errorf("service not deleted once the CRLs are disabled: %v", err)
+	}
+	if got := csCAIssuer(instance, testNS).Spec.CA.CRLDistributionPoints; len(got) != 0 {
+		t.Errorf("got CRL distribution points %v once the CRLs are disabled", got)
+	}
+}
+
+func TestCSCAIssuerOCSPServers(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Revocation: &operatorv1.RevocationSpec{OCSP: &operatorv1.OCSPSpec{Enabled: true}},
+		},
+	}
+	want := "http://" + res.RevocationServiceName + "." + testNS + ".svc" + res.OCSPPath
+	if got := csCAIssuer(instance, testNS).Spec.CA.OCSPServers; len(got) != 1 || got[0] != want {
+		t.Errorf("got OCSP servers %v, want %s", got, want)
+	}
+
+	// the responder answers for another issuer
+	instance.Spec.Revocation.OCSP.IssuerRef = &cmmeta.ObjectReference{Name: "other-ca", Kind: certmanagerv1.ClusterIssuerKind}
+	if got := csCAIssuer(instance, testNS).Spec.CA.OCSPServers; len(got) != 0 {
+		t.Errorf("got OCSP servers %v for an issuer the responder does not answer for", got)
+	}
+
+	r := newTestReconciler(t, instance)
+	if err := r.reconcileRevocationService(instance); err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Namespace: testNS, Name: res.RevocationServiceName}
+	if err := r.Client.Get(context.TODO(), key, &corev1.Service{}); err != nil {
+		t.Errorf("no revocation service for the OCSP responder: %v", err)
+	}
+}
+
+func containsUsage(usages []certmanagerv1.KeyUsage, usage certmanagerv1.KeyUsage) bool {
+	for _, u := range usages {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
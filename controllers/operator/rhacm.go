@@ -0,0 +1,165 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// RhacmIntegrationCondition reports whether the CS CA secret is shared with RHACM
+const RhacmIntegrationCondition = "RhacmIntegration"
+
+// reconcileRhacm shares the CS CA secret into the namespace of the
+// MultiClusterHub through a SecretShare, or through the secret replicator
+// when the SecretShare API is not installed, and removes the share once RHACM
+// is gone or runs an unsupported version. The namespace shared with is
+// recorded on the CertManagerConfig, the share is removed from there.
+func (r *CertManagerReconciler) reconcileRhacm(instance *operatorv1.CertManagerConfig) error {
+	condition := metav1.Condition{Type: RhacmIntegrationCondition}
+
+	rhacmVersion, mchNamespace, err := CheckRhacm(r.Client)
+	switch {
+	case err == nil:
+	case meta.IsNoMatchError(err):
+		if err := r.unshareWithRhacm(instance, res.RhacmNamespace); err != nil {
+			return err
+		}
+		condition.Status, condition.Reason = metav1.ConditionFalse, "RhacmNotFound"
+		condition.Message = "No MultiClusterHub found"
+		return r.updateConditions(instance, condition)
+	case mchNamespace != "":
+		// the MultiClusterHub is still being installed, keep any share as it is
+		condition.Status, condition.Reason = metav1.ConditionUnknown, "VersionUnknown"
+		condition.Message = fmt.Sprintf("Cannot read the version of the MultiClusterHub in %s: %v", mchNamespace, err)
+		return r.updateConditions(instance, condition)
+	default:
+		return err
+	}
+
+	supported, err := rhacmSupported(rhacmVersion)
+	if err != nil || !supported {
+		if err := r.unshareWithRhacm(instance, mchNamespace); err != nil {
+			return err
+		}
+		condition.Status, condition.Reason = metav1.ConditionFalse, "UnsupportedVersion"
+		condition.Message = fmt.Sprintf("RHACM %s is older than %s or cannot be parsed", rhacmVersion, res.RhacmMinVersion)
+		return r.updateConditions(instance, condition)
+	}
+
+	available, err := apiAvailable(r.Kubeclient, res.SecretShareGroupVersion)
+	if err != nil {
+		return err
+	}
+	// the MultiClusterHub moved, the replica in its former namespace is removed
+	if shared := instance.Annotations[res.RhacmSharedNamespaceAnnotation]; shared != "" && shared != mchNamespace {
+		if err := stopReplicatingSecret(r.Client, r.Reader, res.CSCASecretName, r.NS, shared); err != nil {
+			return err
+		}
+	}
+	if err := r.recordRhacmNamespace(instance, mchNamespace); err != nil {
+		return err
+	}
+
+	if !available {
+		// the secret replicator of the operator copies the secret instead
+		err := replicateSecret(r.Client, r.Reader, res.CSCASecretName, r.NS, mchNamespace)
+		if errors.IsNotFound(err) {
+			condition.Status, condition.Reason = metav1.ConditionFalse, "SecretNotFound"
+			condition.Message = fmt.Sprintf("Waiting for %s to be created", res.CSCASecretName)
+			return r.updateConditions(instance, condition)
+		} else if err != nil {
+			return err
+		}
+		condition.Status, condition.Reason = metav1.ConditionTrue, "Replicated"
+		condition.Message = fmt.Sprintf("%s is replicated to RHACM %s in %s", res.CSCASecretName, rhacmVersion, mchNamespace)
+		return r.updateConditions(instance, condition)
+	}
+
+	// the SecretShare takes over from the secret replicator
+	if err := stopReplicatingSecret(r.Client, r.Reader, res.CSCASecretName, r.NS, mchNamespace); err != nil {
+		return err
+	}
+	if err := copySecret(r.Client, res.CSCASecretName, r.NS, mchNamespace, res.RhacmSecretShareCRName); err != nil {
+		return err
+	}
+	condition.Status, condition.Reason = metav1.ConditionTrue, "Shared"
+	condition.Message = fmt.Sprintf("%s is shared with RHACM %s in %s", res.CSCASecretName, rhacmVersion, mchNamespace)
+	return r.updateConditions(instance, condition)
+}
+
+// unshareWithRhacm removes the SecretShare or the replica of the CS CA secret
+// in the RHACM namespace it was shared with, mchNamespace when none was
+// recorded
+func (r *CertManagerReconciler) unshareWithRhacm(instance *operatorv1.CertManagerConfig, mchNamespace string) error {
+	if err := removeSecretShare(r.Client, r.NS, res.RhacmSecretShareCRName); err != nil {
+		return err
+	}
+	if shared := instance.Annotations[res.RhacmSharedNamespaceAnnotation]; shared != "" {
+		mchNamespace = shared
+	}
+	if err := stopReplicatingSecret(r.Client, r.Reader, res.CSCASecretName, r.NS, mchNamespace); err != nil {
+		return err
+	}
+	return r.recordRhacmNamespace(instance, "")
+}
+
+// recordRhacmNamespace annotates the CertManagerConfig with the RHACM
+// namespace the CS CA secret is shared with, or removes the annotation
+func (r *CertManagerReconciler) recordRhacmNamespace(instance *operatorv1.CertManagerConfig, mchNamespace string) error {
+	if instance.Annotations[res.RhacmSharedNamespaceAnnotation] == mchNamespace {
+		return nil
+	}
+	if mchNamespace == "" {
+		delete(instance.Annotations, res.RhacmSharedNamespaceAnnotation)
+	} else {
+		if instance.Annotations == nil {
+			instance.Annotations = map[string]string{}
+		}
+		instance.Annotations[res.RhacmSharedNamespaceAnnotation] = mchNamespace
+	}
+	return r.Client.Update(context.TODO(), instance)
+}
+
+// rhacmSupported returns whether the RHACM version is at least RhacmMinVersion
+func rhacmSupported(rhacmVersion string) (bool, error) {
+	current, err := version.ParseGeneric(rhacmVersion)
+	if err != nil {
+		return false, err
+	}
+	return current.AtLeast(version.MustParseGeneric(res.RhacmMinVersion)), nil
+}
+
+// updateConditions sets the condition in the status of the CertManagerConfig
+func (r *CertManagerReconciler) updateConditions(instance *operatorv1.CertManagerConfig, condition metav1.Condition) error {
+	conditions := append([]metav1.Condition{}, instance.Status.Conditions...)
+	meta.SetStatusCondition(&conditions, condition)
+	if equality.Semantic.DeepEqual(conditions, instance.Status.Conditions) {
+		return nil
+	}
+	instance.Status.Conditions = conditions
+	return r.Client.Status().Update(context.TODO(), instance)
+}
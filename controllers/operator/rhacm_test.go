@@ -0,0 +1,244 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretshare "github.com/IBM/ibm-secretshare-operator/api/v1"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func multiClusterHub(version string) *unstructured.Unstructured {
+	mch := &unstructured.Unstructured{}
+	mch.SetGroupVersionKind(res.RhacmGVK)
+	mch.SetName(res.RhacmCRName)
+	mch.SetNamespace(res.RhacmNamespace)
+	if version != "" {
+		mch.Object["status"] = map[string]interface{}{"currentVersion": version}
+	}
+	return mch
+}
+
+func newRhacmReconciler(t *testing.T, secretShareAPI bool, objs ...client.Object) *CertManagerReconciler {
+	t.Helper()
+	r := newTestReconciler(t, objs...)
+	if err := secretshare.AddToScheme(r.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	kubeclient := kubefake.NewSimpleClientset()
+	if secretShareAPI {
+		kubeclient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: res.SecretShareGroupVersion.String(), APIResources: []metav1.APIResource{{Name: "secretshares", Kind: "SecretShare", Namespaced: true}}},
+		}
+	}
+	r.Kubeclient = kubeclient
+	return r
+}
+
+func rhacmCondition(t *testing.T, r *CertManagerReconciler) *metav1.Condition {
+	t.Helper()
+	instance := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: res.CertManagerInstanceName}, instance); err != nil {
+		t.Fatal(err)
+	}
+	return meta.FindStatusCondition(instance.Status.Conditions, RhacmIntegrationCondition)
+}
+
+func TestReconcileRhacmSharesCSCA(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	mch := multiClusterHub("2.4.1")
+	r := newRhacmReconciler(t, true, instance, mch)
+
+	ctx := context.TODO()
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	share := &secretshare.SecretShare{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.RhacmSecretShareCRName}, share); err != nil {
+		t.Fatal(err)
+	}
+	if got := share.Spec.Secretshares; len(got) != 1 || got[0].Secretname != res.CSCASecretName ||
+		len(got[0].Sharewith) != 1 || got[0].Sharewith[0].Namespace != res.RhacmNamespace {
+		t.Errorf("unexpected secretshares %+v", got)
+	}
+	if cond := rhacmCondition(t, r); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("unexpected %s condition %+v", RhacmIntegrationCondition, cond)
+	}
+
+	// a drifted share is put back in shape
+	share.Spec.Secretshares[0].Sharewith[0].Namespace = "elsewhere"
+	if err := r.Client.Update(ctx, share); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	share = &secretshare.SecretShare{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.RhacmSecretShareCRName}, share); err != nil {
+		t.Fatal(err)
+	}
+	if got := share.Spec.Secretshares[0].Sharewith[0].Namespace; got != res.RhacmNamespace {
+		t.Errorf("share not restored, shared with %s", got)
+	}
+
+	// the share is removed with RHACM
+	if err := r.Client.Delete(ctx, mch); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.RhacmSecretShareCRName}, &secretshare.SecretShare{}); !errors.IsNotFound(err) {
+		t.Errorf("share not removed: %v", err)
+	}
+	if cond := rhacmCondition(t, r); cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "RhacmNotFound" {
+		t.Errorf("unexpected %s condition %+v", RhacmIntegrationCondition, cond)
+	}
+}
+
+func TestReconcileRhacmSkipsUnsupported(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		secretShareAPI bool
+		status         metav1.ConditionStatus
+		reason         string
+	}{
+		{name: "old version", version: "2.2.5", secretShareAPI: true, status: metav1.ConditionFalse, reason: "UnsupportedVersion"},
+		{name: "no version yet", secretShareAPI: true, status: metav1.ConditionUnknown, reason: "VersionUnknown"},
+		{name: "no secretshare API nor secret", version: "2.5.0", status: metav1.ConditionFalse, reason: "SecretNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+			r := newRhacmReconciler(t, tt.secretShareAPI, instance, multiClusterHub(tt.version))
+			if err := r.reconcileRhacm(instance); err != nil {
+				t.Fatal(err)
+			}
+			err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: res.RhacmSecretShareCRName}, &secretshare.SecretShare{})
+			if !errors.IsNotFound(err) {
+				t.Errorf("share created: %v", err)
+			}
+			if cond := rhacmCondition(t, r); cond == nil || cond.Status != tt.status || cond.Reason != tt.reason {
+				t.Errorf("unexpected %s condition %+v", RhacmIntegrationCondition, cond)
+			}
+		})
+	}
+}
+
+func TestReconcileRhacmReplicatesWithoutSecretShare(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        res.CSCASecretName,
+			Namespace:   testNS,
+			Annotations: map[string]string{res.SecretReplicateToAnnotation: "other-ns"},
+		},
+	}
+	mch := multiClusterHub("2.5.0")
+	r := newRhacmReconciler(t, false, instance, mch, secret)
+
+	ctx := context.TODO()
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	got := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), got); err != nil {
+		t.Fatal(err)
+	}
+	if want := "open-cluster-management,other-ns"; got.Annotations[res.SecretReplicateToAnnotation] != want {
+		t.Errorf("got replication targets %q, want %q", got.Annotations[res.SecretReplicateToAnnotation], want)
+	}
+	if _, ok := got.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("secret not labelled for the replicator")
+	}
+	if cond := rhacmCondition(t, r); cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Replicated" {
+		t.Errorf("unexpected %s condition %+v", RhacmIntegrationCondition, cond)
+	}
+
+	// the replication stops with RHACM, other targets are kept
+	if err := r.Client.Delete(ctx, mch); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	got = &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), got); err != nil {
+		t.Fatal(err)
+	}
+	if want := "other-ns"; got.Annotations[res.SecretReplicateToAnnotation] != want {
+		t.Errorf("got replication targets %q, want %q", got.Annotations[res.SecretReplicateToAnnotation], want)
+	}
+}
+
+func TestReconcileRhacmUnsharesRecordedNamespace(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: testNS}}
+	mch := multiClusterHub("2.5.0")
+	mch.SetNamespace("rhacm-ns")
+	r := newRhacmReconciler(t, false, instance, mch, secret)
+
+	ctx := context.TODO()
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	got := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(instance), got); err != nil {
+		t.Fatal(err)
+	}
+	if ns := got.Annotations[res.RhacmSharedNamespaceAnnotation]; ns != "rhacm-ns" {
+		t.Errorf("got shared namespace %q recorded, want rhacm-ns", ns)
+	}
+
+	// the replica is removed from the namespace it was shared with, not the
+	// default RHACM namespace
+	if err := r.Client.Delete(ctx, mch); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileRhacm(instance); err != nil {
+		t.Fatal(err)
+	}
+	replicated := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), replicated); err != nil {
+		t.Fatal(err)
+	}
+	if targets := replicated.Annotations[res.SecretReplicateToAnnotation]; targets != "" {
+		t.Errorf("got replication targets %q, want none", targets)
+	}
+	got = &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(instance), got); err != nil {
+		t.Fatal(err)
+	}
+	if ns, ok := got.Annotations[res.RhacmSharedNamespaceAnnotation]; ok {
+		t.Errorf("shared namespace %q still recorded", ns)
+	}
+}
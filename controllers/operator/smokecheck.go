@@ -0,0 +1,186 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	"github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1/util"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// CertificateIssuanceWorkingCondition reports whether cert-manager issued the
+// test certificate of the last smoke check
+const CertificateIssuanceWorkingCondition = "CertificateIssuanceWorking"
+
+// smokeCheckInterval is how often the smoke check is run
+const smokeCheckInterval = time.Hour
+
+// smokeCheckTimeout is how long the test certificate has to become ready
+const smokeCheckTimeout = 5 * time.Minute
+
+// smokeCheckPollInterval is how often the test certificate is checked while
+// waiting for it
+const smokeCheckPollInterval = 10 * time.Second
+
+// reconcileSmokeCheck issues a test certificate from the smoke check issuer
+// once the operands are available, and then every smokeCheckInterval. Once
+// the certificate is ready, or failed to be, its secret is verified, the test
+// objects are deleted and the result is reported in the
+// CertificateIssuanceWorking condition. It returns when to reconcile again to
+// carry on with the check.
+func (r *CertManagerReconciler) reconcileSmokeCheck(instance *operatorv1.CertManagerConfig) (time.Duration, error) {
+	if ready, message, err := r.operandsReady(instance); err != nil {
+		return 0, err
+	} else if !ready {
+		logd.V(2).Info("Waiting for cert-manager before the smoke check", "reason", message)
+		return 0, nil
+	}
+
+	crt := &certmanagerv1.Certificate{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.SmokeCheckCertName}, crt)
+	if errors.IsNotFound(err) {
+		if wait := smokeCheckInterval - time.Since(r.smokeCheckTime); !r.smokeCheckTime.IsZero() && wait > 0 {
+			return wait, nil
+		}
+		logd.Info("Starting the smoke check of cert-manager")
+		return smokeCheckPollInterval, r.startSmokeCheck(instance)
+	} else if err != nil {
+		return 0, err
+	}
+
+	// an unreadable start time fails the check, rather than waiting forever
+	start, _ := time.Parse(time.RFC3339, crt.Annotations[res.SmokeCheckStartAnno])
+	if !util.IsCertificateReady(crt) {
+		if time.Since(start) < smokeCheckTimeout {
+			return smokeCheckPollInterval, nil
+		}
+		message := fmt.Sprintf("The test certificate %s was not ready after %s", res.SmokeCheckCertName, smokeCheckTimeout)
+		if ready := util.GetCertificateCondition(crt, certmanagerv1.CertificateConditionReady); ready != nil && ready.Message != "" {
+			message += ": " + ready.Message
+		}
+		return smokeCheckInterval, r.finishSmokeCheck(instance, metav1.ConditionFalse, "NotReady", message)
+	}
+	if err := r.verifySmokeCheckSecret(); err != nil {
+		return smokeCheckInterval, r.finishSmokeCheck(instance, metav1.ConditionFalse, "InvalidSecret", err.Error())
+	}
+	return smokeCheckInterval, r.finishSmokeCheck(instance, metav1.ConditionTrue, "Issued",
+		fmt.Sprintf("The test certificate %s was issued in %s", res.SmokeCheckCertName, time.Since(start).Round(time.Second)))
+}
+
+// startSmokeCheck creates the smoke check issuer and its test certificate
+func (r *CertManagerReconciler) startSmokeCheck(instance *operatorv1.CertManagerConfig) error {
+	issuer := res.Issuer.DeepCopy()
+	issuer.Namespace = r.NS
+	if err := controllerutil.SetControllerReference(instance, issuer, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.applyIssuer(issuer); err != nil {
+		return err
+	}
+
+	crt := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        res.SmokeCheckCertName,
+			Namespace:   r.NS,
+			Annotations: map[string]string{res.SmokeCheckStartAnno: time.Now().UTC().Format(time.RFC3339)},
+		},
+		Spec: certmanagerv1.CertificateSpec{
+			CommonName:  res.SmokeCheckDNSName,
+			DNSNames:    []string{res.SmokeCheckDNSName},
+			SecretName:  res.SmokeCheckSecretName,
+			Duration:    &metav1.Duration{Duration: res.SmokeCheckCertDuration},
+			RenewBefore: &metav1.Duration{Duration: res.SmokeCheckCertDuration / 2},
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  res.SmokeCheckIssuerName,
+				Kind:  certmanagerv1.IssuerKind,
+				Group: "cert-manager.io",
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, crt, r.Scheme); err != nil {
+		return err
+	}
+	return r.Client.Create(context.TODO(), crt)
+}
+
+// verifySmokeCheckSecret checks that the secret of the test certificate holds
+// a key pair for the requested names
+func (r *CertManagerReconciler) verifySmokeCheckSecret() error {
+	// the secret is not labelled for the cache of the operator
+	secret := &corev1.Secret{}
+	if err := r.Reader.Get(context.TODO(), types.NamespacedName{Namespace: r.NS, Name: res.SmokeCheckSecretName}, secret); err != nil {
+		return fmt.Errorf("cannot read the secret %s of the test certificate: %v", res.SmokeCheckSecretName, err)
+	}
+	pair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("the secret %s does not hold a valid key pair: %v", res.SmokeCheckSecretName, err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("the certificate in the secret %s cannot be parsed: %v", res.SmokeCheckSecretName, err)
+	}
+	if leaf.Subject.CommonName != res.SmokeCheckDNSName || len(leaf.DNSNames) != 1 || leaf.DNSNames[0] != res.SmokeCheckDNSName {
+		return fmt.Errorf("the certificate in the secret %s is for %q %v, not %s", res.SmokeCheckSecretName,
+			leaf.Subject.CommonName, leaf.DNSNames, res.SmokeCheckDNSName)
+	}
+	return nil
+}
+
+// finishSmokeCheck deletes the test objects and reports the result of the
+// smoke check
+func (r *CertManagerReconciler) finishSmokeCheck(instance *operatorv1.CertManagerConfig, status metav1.ConditionStatus, reason, message string) error {
+	for _, obj := range []client.Object{
+		&certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: r.NS, Name: res.SmokeCheckCertName}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: r.NS, Name: res.SmokeCheckSecretName}},
+		&certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Namespace: r.NS, Name: res.SmokeCheckIssuerName}},
+	} {
+		if err := r.Client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	r.smokeCheckTime = time.Now()
+
+	if status == metav1.ConditionTrue {
+		logd.Info("Smoke check of cert-manager succeeded", "message", message)
+		certificateIssuanceWorking.Set(1)
+	} else {
+		logd.Info("Smoke check of cert-manager failed", "reason", reason, "message", message)
+		r.updateEvent(instance, message, corev1.EventTypeWarning, "SmokeCheckFailed")
+		certificateIssuanceWorking.Set(0)
+	}
+	return r.updateConditions(instance, metav1.Condition{
+		Type:    CertificateIssuanceWorkingCondition,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
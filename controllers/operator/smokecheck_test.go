@@ -0,0 +1,209 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package operator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// issueSmokeCheck plays cert-manager, marking the test certificate ready
+// with a secret for the DNS name
+func issueSmokeCheck(t *testing.T, r *CertManagerReconciler, dnsName string) {
+	t.Helper()
+	ctx := context.TODO()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.SmokeCheckSecretName, Namespace: testNS},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	setSmokeCheckReady(t, r, cmmeta.ConditionTrue, "Certificate is up to date")
+}
+
+func setSmokeCheckReady(t *testing.T, r *CertManagerReconciler, status cmmeta.ConditionStatus, message string) {
+	t.Helper()
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNS, Name: res.SmokeCheckCertName}, crt); err != nil {
+		t.Fatal(err)
+	}
+	crt.Status.Conditions = []certmanagerv1.CertificateCondition{{Type: certmanagerv1.CertificateConditionReady, Status: status, Message: message}}
+	if err := r.Client.Update(context.TODO(), crt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileSmokeCheck(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1))
+	ctx := context.TODO()
+
+	wait, err := r.reconcileSmokeCheck(instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != smokeCheckPollInterval {
+		t.Errorf("got requeue after %v while the test certificate is issued", wait)
+	}
+	issuer := &certmanagerv1.Issuer{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.SmokeCheckIssuerName}, issuer); err != nil {
+		t.Fatal(err)
+	}
+	if issuer.Spec.SelfSigned == nil {
+		t.Errorf("%s is not self-signed", res.SmokeCheckIssuerName)
+	}
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.SmokeCheckCertName}, crt); err != nil {
+		t.Fatal(err)
+	}
+	if crt.Spec.IssuerRef.Name != res.SmokeCheckIssuerName || crt.Spec.SecretName != res.SmokeCheckSecretName ||
+		crt.Annotations[res.SmokeCheckStartAnno] == "" {
+		t.Errorf("unexpected test certificate %+v", crt)
+	}
+
+	// still waiting for cert-manager
+	if _, err := r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	if meta.FindStatusCondition(instance.Status.Conditions, CertificateIssuanceWorkingCondition) != nil {
+		t.Error("smoke check reported before the test certificate is ready")
+	}
+
+	issueSmokeCheck(t, r, res.SmokeCheckDNSName)
+	if wait, err = r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	if wait != smokeCheckInterval {
+		t.Errorf("got requeue after %v once the smoke check succeeded", wait)
+	}
+	if !meta.IsStatusConditionTrue(instance.Status.Conditions, CertificateIssuanceWorkingCondition) {
+		t.Errorf("unexpected conditions %+v", instance.Status.Conditions)
+	}
+	if testutil.ToFloat64(certificateIssuanceWorking) != 1 {
+		t.Error("the smoke check metric is not set")
+	}
+	for name, obj := range map[string]client.Object{
+		res.SmokeCheckCertName:   &certmanagerv1.Certificate{},
+		res.SmokeCheckSecretName: &corev1.Secret{},
+		res.SmokeCheckIssuerName: &certmanagerv1.Issuer{},
+	} {
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: name}, obj); !errors.IsNotFound(err) {
+			t.Errorf("%s not cleaned up: %v", name, err)
+		}
+	}
+
+	// the next check waits for the interval
+	if wait, err = r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	if wait <= 0 || wait > smokeCheckInterval {
+		t.Errorf("got requeue after %v between smoke checks", wait)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.SmokeCheckCertName}, crt); !errors.IsNotFound(err) {
+		t.Errorf("smoke check started again before the interval: %v", err)
+	}
+}
+
+func TestReconcileSmokeCheckFailures(t *testing.T) {
+	instance := &operatorv1.CertManagerConfig{ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName}}
+	r := newTestReconciler(t, instance, deployment(res.CertManagerControllerName, 1))
+	ctx := context.TODO()
+
+	// a secret for other names fails the check
+	if _, err := r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	issueSmokeCheck(t, r, "other.example.com")
+	if _, err := r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	condition := meta.FindStatusCondition(instance.Status.Conditions, CertificateIssuanceWorkingCondition)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InvalidSecret" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	if testutil.ToFloat64(certificateIssuanceWorking) != 0 {
+		t.Error("the smoke check metric is not reset")
+	}
+
+	// a certificate not ready in time fails the check
+	r.smokeCheckTime = time.Time{}
+	if _, err := r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	setSmokeCheckReady(t, r, cmmeta.ConditionFalse, "Issuing certificate as Secret does not exist")
+	crt := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.SmokeCheckCertName}, crt); err != nil {
+		t.Fatal(err)
+	}
+	crt.Annotations[res.SmokeCheckStartAnno] = time.Now().Add(-smokeCheckTimeout).UTC().Format(time.RFC3339)
+	if err := r.Client.Update(ctx, crt); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileSmokeCheck(instance); err != nil {
+		t.Fatal(err)
+	}
+	condition = meta.FindStatusCondition(instance.Status.Conditions, CertificateIssuanceWorkingCondition)
+	if condition == nil || condition.Reason != "NotReady" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(crt), &certmanagerv1.Certificate{}); !errors.IsNotFound(err) {
+		t.Errorf("test certificate not cleaned up after a timeout: %v", err)
+	}
+}
@@ -25,16 +25,23 @@ import (
 	"reflect"
 
 	utilyaml "github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/runtime/serializer/streaming"
 	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	secretshare "github.com/IBM/ibm-secretshare-operator/api/v1"
+
+	"github.com/ibm/ibm-cert-manager-operator/controllers/secretreplicator"
 )
 
 func containsString(source []string, str string) bool {
@@ -79,14 +86,102 @@ func copySecret(client client.Client, secretToCopy string, srcNamespace string,
 		},
 	}
 
-	// Create the secretshare CR to copy the secret
-	err := client.Create(context.TODO(), secretShareCR)
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return fmt.Errorf("could not create resource: %v", err)
+	// Create the secretshare CR to copy the secret, or put it back in shape
+	existing := &secretshare.SecretShare{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: secretShareCRName, Namespace: srcNamespace}, existing)
+	if errors.IsNotFound(err) {
+		if err := client.Create(context.TODO(), secretShareCR); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("could not create resource: %v", err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not get resource: %v", err)
+	}
+
+	if reflect.DeepEqual(existing.Spec, secretShareCR.Spec) {
+		return nil
 	}
+	existing.Spec = secretShareCR.Spec
+	if err := client.Update(context.TODO(), existing); err != nil {
+		return fmt.Errorf("could not update resource: %v", err)
+	}
+	return nil
+}
 
+// removeSecretShare deletes the secretshare CR created by copySecret. There is
+// nothing to delete when the secretshare API is not installed.
+func removeSecretShare(client client.Client, namespace string, secretShareCRName string) error {
+	secretShareCR := &secretshare.SecretShare{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretShareCRName,
+			Namespace: namespace,
+		},
+	}
+	err := client.Delete(context.TODO(), secretShareCR)
+	if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("could not delete resource: %v", err)
+	}
 	return nil
+}
+
+// replicateSecret copies the secret to another namespace with the secret
+// replicator, when the secretshare API is not installed
+func replicateSecret(client client.Client, reader client.Reader, secretToCopy string, srcNamespace string, destNamespace string) error {
+	return updateReplicationTargets(client, reader, secretToCopy, srcNamespace, func(targets []string) []string {
+		return append(targets, destNamespace)
+	})
+}
 
+// stopReplicatingSecret stops the secret replicator from copying the secret
+// to another namespace, which deletes the copy
+func stopReplicatingSecret(client client.Client, reader client.Reader, secretToCopy string, srcNamespace string, destNamespace string) error {
+	err := updateReplicationTargets(client, reader, secretToCopy, srcNamespace, func(targets []string) []string {
+		kept := []string{}
+		for _, target := range targets {
+			if target != destNamespace {
+				kept = append(kept, target)
+			}
+		}
+		return kept
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func updateReplicationTargets(client client.Client, reader client.Reader, secretName string, namespace string, update func([]string) []string) error {
+	// the secret is not cached until it has the watch label
+	secret := &corev1.Secret{}
+	if err := reader.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return err
+	}
+	targets := secretreplicator.ReplicationTargets(secret)
+	updated := secret.DeepCopy()
+	secretreplicator.SetReplicationTargets(updated, update(targets))
+	if reflect.DeepEqual(updated.ObjectMeta, secret.ObjectMeta) {
+		return nil
+	}
+	return client.Update(context.TODO(), updated)
+}
+
+// apiAvailable returns whether the API server serves the group version
+func apiAvailable(kubeclient kubernetes.Interface, groupVersion schema.GroupVersion) (bool, error) {
+	groups, err := kubeclient.Discovery().ServerGroups()
+	if err != nil {
+		return false, err
+	}
+	for _, group := range groups.Groups {
+		if group.Name != groupVersion.Group {
+			continue
+		}
+		for _, version := range group.Versions {
+			if version.Version == groupVersion.Version {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
 // YamlToObjects convert YAML content to unstructured objects
@@ -18,11 +18,13 @@ package resources
 
 import (
 	"os"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // TrueVar the variable representing the boolean value true
@@ -176,32 +178,38 @@ var initialDelaySecondsLiveness int32 = 60
 var timeoutSecondsLiveness int32 = 10
 var periodSecondsLiveness int32 = 30
 var failureThresholdLiveness int32 = 10
-var livenessExecActionController = v1.ExecAction{
-	Command: []string{"sh", "-c", "pgrep cert-manager -l"},
+
+// the controller has no health endpoint, its metrics listener is open as long
+// as it runs. Only the port is checked, scraping /metrics on every probe
+// would render all the metrics for nothing.
+var livenessTCPSocketController = v1.TCPSocketAction{
+	Port: intstr.FromInt(ControllerMetricsPort),
 }
 var livenessExecActionCainjector = v1.ExecAction{
 	Command: []string{"sh", "-c", "pgrep cainjector -l"},
 }
-var livenessExecActionWebhook = v1.ExecAction{
-	Command: []string{"sh", "-c", "pgrep webhook -l"},
+// the webhook of cert-manager 0.12 only serves /healthz on its health port
+var livenessHTTPGetWebhook = v1.HTTPGetAction{
+	Path:   "/healthz",
+	Port:   intstr.FromInt(WebhookHealthPort),
+	Scheme: v1.URISchemeHTTP,
 }
 
 var initialDelaySecondsReadiness int32 = 60
 var timeoutSecondsReadiness int32 = 10
 var periodSecondsReadiness int32 = 30
 var failureThresholdReadiness int32 = 10
-var readinessExecActionController = v1.ExecAction{
-	Command: []string{"sh", "-c", "exec echo start cert-manager"},
-}
-var readinessExecActionCainjector = v1.ExecAction{
-	Command: []string{"sh", "-c", "exec echo start cert-manager cainjector"},
-}
-var readinessExecActionWebhook = v1.ExecAction{
-	Command: []string{"sh", "-c", "exec echo start cert-manager webhook"},
-}
+var readinessTCPSocketController = livenessTCPSocketController
+
+// the cainjector serves nothing, it is ready as long as it runs
+var readinessExecActionCainjector = livenessExecActionCainjector
+var readinessHTTPGetWebhook = livenessHTTPGetWebhook
 
 // Cert-manager args
 
+// WebhookHealthPort is the port cert-manager-webhook serves /healthz on
+const WebhookHealthPort = 6080
+
 // WebhookServingSecret is the name of tls secret used for serving the cert-manager-webhook
 const WebhookServingSecret = "cert-manager-webhook-ca"
 
@@ -245,6 +253,14 @@ var CSCAIssuerLabelMap = map[string]string{
 	"app.kubernetes.io/instance":   "ibm-cert-manager-operator",
 }
 
+// CSCAManagedAnnotation marks the objects of the CS CA chain created by the
+// operator. The ones without it were created by another component and are
+// left alone.
+const CSCAManagedAnnotation = "operator.ibm.com/created-by-cert-manager-operator"
+
+// CSCAManagedAnnotationMap is the annotations of the objects of the CS CA chain created by the operator
+var CSCAManagedAnnotationMap = map[string]string{CSCAManagedAnnotation: "true"}
+
 //CSCAIssuerName is the name of the CS CA Issuer
 const CSCAIssuerName = "cs-ca-issuer"
 
@@ -254,6 +270,21 @@ const CSCACertName = "cs-ca-certificate"
 //CSCASecretName is the name of the CA certificate secret
 const CSCASecretName = "cs-ca-certificate-secret"
 
+//CSCASelfSignedIssuerName is the name of the self-signed Issuer of the CS CA certificate
+const CSCASelfSignedIssuerName = "cs-ss-issuer"
+
+// DefaultCSCAKeyAlgorithm is the default private key algorithm of the CS CA certificate
+const DefaultCSCAKeyAlgorithm = "RSA"
+
+// DefaultCSCADuration is the default lifetime of the CS CA certificate
+const DefaultCSCADuration = 17520 * time.Hour
+
+// DefaultCSCARenewBefore is the default time before its expiry the CS CA certificate is renewed
+const DefaultCSCARenewBefore = 720 * time.Hour
+
+// DefaultCSCAImportMinValidity is the default time an imported CS CA certificate must still be valid for
+const DefaultCSCAImportMinValidity = 720 * time.Hour
+
 //RhacmNamespace is the namespace where RHACM is installed
 const RhacmNamespace = "open-cluster-management"
 
@@ -263,6 +294,15 @@ const RhacmCRName = "multiclusterhub"
 //RhacmSecretShareCRName is the Secret Share CR Name that copies the cs-ca-certificate-secret
 var RhacmSecretShareCRName = "rhacm-cs-ca-certificate-secret-share"
 
+//RhacmSharedNamespaceAnnotation records on the CertManagerConfig the RHACM namespace the cs-ca-certificate-secret is shared with, so the share is removed from there
+const RhacmSharedNamespaceAnnotation = "operator.ibm.com/rhacm-shared-namespace"
+
+//RhacmMinVersion is the oldest RHACM version the cs-ca-certificate-secret is shared with
+const RhacmMinVersion = "2.3.0"
+
+//SecretShareGroupVersion is the API of the SecretShare CRs of the ibm-secretshare-operator
+var SecretShareGroupVersion = schema.GroupVersion{Group: "ibmcpcs.ibm.com", Version: "v1"}
+
 //RhacmGVK identifies the RHACM CRD
 var RhacmGVK = schema.GroupVersionKind{
 	Group:   "operator.open-cluster-management.io",
@@ -276,6 +316,124 @@ const DefaultEnableCertRefresh = true
 // DefaultCANames is the default CA names for which the leaf certs need to be refreshed
 var DefaultCANames = []string{"cs-ca-certificate", "mongodb-root-ca-cert"}
 
+// DefaultRefreshMaxConcurrent is the default number of leaf certs re-issued at the same time when their CA is refreshed
+const DefaultRefreshMaxConcurrent = 10
+
+// DefaultRefreshInterval is the default minimum time between starting the re-issuance of two leaf certs
+const DefaultRefreshInterval = 2 * time.Second
+
+// DefaultRefreshTimeout is the default time the re-issuance of a leaf cert is waited for during a refresh
+const DefaultRefreshTimeout = 10 * time.Minute
+
+// DefaultTrustOverlapDuration is the default time the previous CA stays in the trust bundle of a rotated CA
+const DefaultTrustOverlapDuration = 24 * time.Hour
+
+// TrustBundleSuffix is appended to the name of a CA certificate to name the ConfigMap holding its trust bundle
+const TrustBundleSuffix = "-trust-bundle"
+
+// TrustBundleKey is the key of the trust bundle ConfigMaps holding the PEM encoded CA certificates
+const TrustBundleKey = "ca-bundle.crt"
+
+// TrustBundleLabel is set on the ConfigMaps written for a TrustBundle to the name of the TrustBundle
+const TrustBundleLabel = "operator.ibm.com/trust-bundle"
+
+// TrustBundleHashAnnotation holds the hash of the content of a TrustBundle ConfigMap, so the truststores are only rebuilt when it changes
+const TrustBundleHashAnnotation = "operator.ibm.com/trust-bundle-hash"
+
+// DefaultTrustStorePassword is the default password of the JKS and PKCS12 truststores of a TrustBundle
+const DefaultTrustStorePassword = "changeit"
+
+// SecretReplicateToAnnotation lists the namespaces, separated by commas, a
+// secret labelled with SecretWatchLabel is replicated to
+const SecretReplicateToAnnotation = "operator.ibm.com/replicate-to"
+
+// SecretReplicatedFromAnnotation is set on the replicas of a secret to the namespace/name of the source secret
+const SecretReplicatedFromAnnotation = "operator.ibm.com/replicated-from"
+
+// SecretReplicaLabel marks the secrets written by the secret replicator
+const SecretReplicaLabel = "operator.ibm.com/secret-replica"
+
+// RevocationServiceName is the Service in front of the CRLs and the OCSP responder served by the operator
+const RevocationServiceName = "ibm-cert-manager-revocation"
+
+// RevocationServerPort is the port of the operator serving the CRLs and the OCSP responder
+const RevocationServerPort = 8089
+
+// OperatorLabelMap selects the operator pods
+var OperatorLabelMap = map[string]string{"name": "ibm-cert-manager-operator"}
+
+// CRLKey is the key of the DER encoded CRL in the ConfigMap a CRL is published to
+const CRLKey = "ca.crl"
+
+// CRLIssuerLabel is set on the CRL ConfigMaps to the kind of their issuer
+const CRLIssuerLabel = "operator.ibm.com/crl-issuer"
+
+// CRLHashAnnotation holds the hash of the revocations and the CA of a CRL ConfigMap, so the CRL is only signed again when they change or the CRL gets stale
+const CRLHashAnnotation = "operator.ibm.com/crl-hash"
+
+// DefaultCRLValidity is the default time between the thisUpdate and the nextUpdate of a CRL
+const DefaultCRLValidity = 24 * time.Hour
+
+// DefaultCRLRefreshBefore is how long before its nextUpdate a CRL is published again by default
+const DefaultCRLRefreshBefore = 8 * time.Hour
+
+// OCSPPath is the path the OCSP responder of the operator is served at
+const OCSPPath = "/ocsp"
+
+// OCSPSignerCertName is the delegated OCSP signing certificate of the OCSP responder, issued by the issuer it answers for
+const OCSPSignerCertName = "ibm-cert-manager-ocsp-signer"
+
+// OCSPSignerSecretName is the secret of the OCSP signing certificate
+const OCSPSignerSecretName = "ibm-cert-manager-ocsp-signer-secret"
+
+// OCSPRevokedSerialsKey is the key of the ConfigMap listing the serials revoked for the OCSP responder
+const OCSPRevokedSerialsKey = "serials"
+
+// DefaultOCSPResponseValidity is the default time between the thisUpdate and the nextUpdate of the OCSP responses
+const DefaultOCSPResponseValidity = time.Hour
+
+// ControllerMetricsPort is the port cert-manager-controller serves its metrics on
+const ControllerMetricsPort = 9402
+
+// ControllerMetricsPortName is the name of the metrics port of cert-manager-controller and of its Service
+const ControllerMetricsPortName = "http-metrics"
+
+// ControllerMetricsServiceName is the Service in front of the metrics of cert-manager-controller
+const ControllerMetricsServiceName = "cert-manager-controller-metrics"
+
+// ServiceMonitorName is the ServiceMonitor scraping the metrics of cert-manager-controller
+const ServiceMonitorName = "cert-manager-controller"
+
+// OperatorMetricsPort is the port the operator serves its metrics on, see --metrics-bind-address
+const OperatorMetricsPort = 8080
+
+// OperatorMetricsPortName is the name of the metrics port of the operator and of its Service
+const OperatorMetricsPortName = "metrics"
+
+// OperatorMetricsServiceName is the Service in front of the metrics of the operator
+const OperatorMetricsServiceName = "ibm-cert-manager-operator-metrics"
+
+// OperatorServiceMonitorName is the ServiceMonitor scraping the metrics of the operator
+const OperatorServiceMonitorName = "ibm-cert-manager-operator"
+
+// PrometheusRuleName is the PrometheusRule with the alerts on the certificates and issuers
+const PrometheusRuleName = "cert-manager-alerts"
+
+// MonitoringGroupVersion is the API of the ServiceMonitors and PrometheusRules of the Prometheus operator
+var MonitoringGroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+
+// DefaultScrapeInterval is how often the metrics of cert-manager-controller are scraped by default
+const DefaultScrapeInterval = "60s"
+
+// DefaultCertificateExpiryThreshold is how long before its expiry a certificate raises an alert by default
+const DefaultCertificateExpiryThreshold = 21 * 24 * time.Hour
+
+// NotificationSignatureHeader holds the HMAC-SHA256 signature of the payload of a certificate notification, as sha256=<hex>
+const NotificationSignatureHeader = "X-Certificate-Notification-Signature"
+
+// NotificationIDHeader holds the key of a certificate notification, the same for all the attempts to deliver it
+const NotificationIDHeader = "X-Certificate-Notification-Id"
+
 // CertManager instance name
 const CertManagerInstanceName = "default"
 
@@ -290,4 +448,19 @@ const (
 	OperatorGeneratedAnno = "ibm-cert-manager-operator-generated"
 	ProperV1Label         = "ibm-cert-manager-operator/conditionally-generated-v1"
 	RefreshCALabel        = "ibm-cert-manager-operator/refresh-ca-chain"
+	// CRDProtectionLabel marks the cert-manager CRDs guarded by the CRD deletion webhook
+	CRDProtectionLabel = "ibm-cert-manager-operator/crd-deletion-protection"
+	// CRDDeletionOverrideAnno allows a protected CRD to be deleted even if cert-manager resources still exist
+	CRDDeletionOverrideAnno = "ibm-cert-manager-operator/allow-crd-deletion"
 )
+
+// ProtectedCRDs is the list of cert-manager CRDs that can't be deleted while
+// Certificates, Issuers or ClusterIssuers still exist in the cluster
+var ProtectedCRDs = []string{
+	"certificates.cert-manager.io",
+	"certificaterequests.cert-manager.io",
+	"issuers.cert-manager.io",
+	"clusterissuers.cert-manager.io",
+	"orders.acme.cert-manager.io",
+	"challenges.acme.cert-manager.io",
+}
@@ -17,6 +17,7 @@
 package resources
 
 import (
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -77,7 +78,7 @@ var controllerContainer = corev1.Container{
 	},
 	LivenessProbe: &corev1.Probe{
 		Handler: corev1.Handler{
-			Exec: &livenessExecActionController,
+			TCPSocket: &livenessTCPSocketController,
 		},
 		InitialDelaySeconds: initialDelaySecondsLiveness,
 		TimeoutSeconds:      timeoutSecondsLiveness,
@@ -86,7 +87,7 @@ var controllerContainer = corev1.Container{
 	},
 	ReadinessProbe: &corev1.Probe{
 		Handler: corev1.Handler{
-			Exec: &readinessExecActionController,
+			TCPSocket: &readinessTCPSocketController,
 		},
 		InitialDelaySeconds: initialDelaySecondsReadiness,
 		TimeoutSeconds:      timeoutSecondsReadiness,
@@ -101,7 +102,7 @@ var webhookContainer = corev1.Container{
 	Name:            CertManagerWebhookName,
 	Image:           webhookImage,
 	ImagePullPolicy: pullPolicy,
-	Args:            []string{"--v=2", "--secure-port=10250", "--dynamic-serving-ca-secret-namespace=" + DeployNamespace, "--dynamic-serving-ca-secret-name=" + WebhookServingSecret, "--dynamic-serving-dns-names=" + strings.Join([]string{CertManagerWebhookName, CertManagerWebhookName + "." + DeployNamespace, CertManagerWebhookName + "." + DeployNamespace + ".svc"}, ",")},
+	Args:            []string{"--v=2", "--secure-port=10250", "--healthz-port=" + strconv.Itoa(WebhookHealthPort), "--dynamic-serving-ca-secret-namespace=" + DeployNamespace, "--dynamic-serving-ca-secret-name=" + WebhookServingSecret, "--dynamic-serving-dns-names=" + strings.Join([]string{CertManagerWebhookName, CertManagerWebhookName + "." + DeployNamespace, CertManagerWebhookName + "." + DeployNamespace + ".svc"}, ",")},
 	Env: []corev1.EnvVar{
 		{
 			Name: "POD_NAMESPACE",
@@ -121,7 +122,7 @@ var webhookContainer = corev1.Container{
 	},
 	LivenessProbe: &corev1.Probe{
 		Handler: corev1.Handler{
-			Exec: &livenessExecActionWebhook,
+			HTTPGet: &livenessHTTPGetWebhook,
 		},
 		InitialDelaySeconds: initialDelaySecondsLiveness,
 		TimeoutSeconds:      timeoutSecondsLiveness,
@@ -130,7 +131,7 @@ var webhookContainer = corev1.Container{
 	},
 	ReadinessProbe: &corev1.Probe{
 		Handler: corev1.Handler{
-			Exec: &readinessExecActionWebhook,
+			HTTPGet: &readinessHTTPGetWebhook,
 		},
 		InitialDelaySeconds: initialDelaySecondsReadiness,
 		TimeoutSeconds:      timeoutSecondsReadiness,
@@ -17,17 +17,38 @@ limitations under the License.
 package resources
 
 import (
+	"time"
+
 	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// SmokeCheckIssuerName is the self-signed issuer of the smoke check certificate
+const SmokeCheckIssuerName = "smoke-check-issuer"
+
+// SmokeCheckCertName is the test certificate issued by the smoke check
+const SmokeCheckCertName = "smoke-check-certificate"
+
+// SmokeCheckSecretName is the secret of the smoke check certificate
+const SmokeCheckSecretName = "smoke-check-certificate-secret"
+
+// SmokeCheckDNSName is the DNS name requested in the smoke check certificate
+const SmokeCheckDNSName = "smoke-check.ibm-cert-manager.local"
+
+// SmokeCheckStartAnno holds when the smoke check certificate was requested
+const SmokeCheckStartAnno = "ibm-cert-manager-operator/smoke-check-start"
+
+// SmokeCheckCertDuration is the lifetime of the smoke check certificate, which is deleted once checked
+const SmokeCheckCertDuration = time.Hour
+
+// Issuer is the self-signed issuer of the smoke check certificate
 var Issuer = certmanagerv1.Issuer{
 	TypeMeta: metav1.TypeMeta{
 		Kind:       "Issuer",
 		APIVersion: "cert-manager.io/v1",
 	},
 	ObjectMeta: metav1.ObjectMeta{
-		Name:      "smoke-check-issuer",
+		Name:      SmokeCheckIssuerName,
 		Namespace: DeployNamespace,
 	},
 	Spec: certmanagerv1.IssuerSpec{
@@ -53,6 +53,15 @@ func GetImageID(imageRegistry, imageName, defaultImageVersion, imagePostfix, env
 	return imageID
 }
 
+// CRLPath returns the path the CRL of an Issuer, or of a ClusterIssuer when
+// namespace is empty, is served at
+func CRLPath(namespace, issuerName string) string {
+	if namespace == "" {
+		return "/crl/clusterissuers/" + issuerName + ".crl"
+	}
+	return "/crl/issuers/" + namespace + "/" + issuerName + ".crl"
+}
+
 // GetDeployNamespace returns the namespace cert manager operator is deployed in
 func GetDeployNamespace() string {
 	ns, _ := os.LookupEnv("DEPLOYED_NAMESPACE")
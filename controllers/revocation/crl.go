@@ -0,0 +1,143 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// reasonCodes are the CRL reason codes of RFC 5280, by the names of the
+// CertificateRevocation API
+var reasonCodes = map[string]int{
+	"unspecified":          0,
+	"keyCompromise":        1,
+	"cACompromise":         2,
+	"affiliationChanged":   3,
+	"superseded":           4,
+	"cessationOfOperation": 5,
+	"certificateHold":      6,
+	"privilegeWithdrawn":   9,
+}
+
+// oidReasonCode is the CRL entry extension holding the reason code
+var oidReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+// revokedCertificate is a certificate revoked by a CertificateRevocation
+type revokedCertificate struct {
+	serial         *big.Int
+	revocationTime time.Time
+	reason         string
+}
+
+// signingCA is the keypair of a CA issuer
+type signingCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// loadSigningCA reads the CA keypair from the secret of a CA issuer
+func loadSigningCA(secret *corev1.Secret) (*signingCA, error) {
+	keypair, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA keypair in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	cert, err := x509.ParseCertificate(keypair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keypair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the key in secret %s/%s cannot sign", secret.Namespace, secret.Name)
+	}
+	return &signingCA{cert: cert, key: key}, nil
+}
+
+// parseSerial parses a hexadecimal serial number, with or without colons
+func parseSerial(serial string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(strings.TrimPrefix(strings.ToLower(serial), "0x"), ":", ""), 16)
+	if !ok || n.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid serial number %q", serial)
+	}
+	return n, nil
+}
+
+// formatSerial formats a serial number the way the CertificateRevocation
+// status reports it
+func formatSerial(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+// sortRevoked sorts the revoked certificates by serial number, so the hash
+// and the CRL don't depend on the order they were listed in
+func sortRevoked(revoked []revokedCertificate) {
+	sort.Slice(revoked, func(i, j int) bool {
+		return revoked[i].serial.Cmp(revoked[j].serial) < 0
+	})
+}
+
+// crlHash identifies the content of a CRL besides its dates and number
+func crlHash(ca *x509.Certificate, revoked []revokedCertificate) string {
+	h := sha256.New()
+	h.Write(ca.Raw)
+	for _, entry := range revoked {
+		fmt.Fprintf(h, "\n%s %d %s", entry.serial.Text(16), entry.revocationTime.Unix(), entry.reason)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// buildCRL returns the DER encoded CRL of the CA listing the revoked
+// certificates
+func buildCRL(ca *signingCA, revoked []revokedCertificate, number *big.Int, thisUpdate time.Time, validity time.Duration) ([]byte, error) {
+	entries := make([]pkix.RevokedCertificate, 0, len(revoked))
+	for _, entry := range revoked {
+		revokedCert := pkix.RevokedCertificate{
+			SerialNumber:   entry.serial,
+			RevocationTime: entry.revocationTime.UTC(),
+		}
+		// the reason is omitted rather than set to unspecified, per RFC 5280
+		if code := reasonCodes[entry.reason]; code != 0 {
+			value, err := asn1.Marshal(asn1.Enumerated(code))
+			if err != nil {
+				return nil, err
+			}
+			revokedCert.Extensions = []pkix.Extension{{Id: oidReasonCode, Value: value}}
+		}
+		entries = append(entries, revokedCert)
+	}
+
+	template := &x509.RevocationList{
+		RevokedCertificates: entries,
+		Number:              number,
+		ThisUpdate:          thisUpdate.UTC(),
+		NextUpdate:          thisUpdate.Add(validity).UTC(),
+	}
+	return x509.CreateRevocationList(rand.Reader, template, ca.cert, ca.key)
+}
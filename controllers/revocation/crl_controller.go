@@ -0,0 +1,391 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+var logd = log.Log.WithName("controller_revocation")
+
+// minCRLRequeue is the shortest time before a CRL is checked again, whatever
+// its nextUpdate
+const minCRLRequeue = time.Minute
+
+// PublishedCondition is the condition of a CertificateRevocation reporting
+// whether the certificate is listed in the CRL of its issuer
+const PublishedCondition = "Published"
+
+// CRLReconciler publishes a CRL for each CA Issuer and ClusterIssuer, listing
+// the certificates of their CertificateRevocations. The requests are the
+// namespace and name of an Issuer, or the name of a ClusterIssuer.
+type CRLReconciler struct {
+	Client   client.Client
+	Reader   client.Reader
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Store    *Store
+	NS       string
+	// ClusterRevocationNamespaces are the namespaces, besides NS, the
+	// revocations of ClusterIssuers are honoured from
+	ClusterRevocationNamespaces []string
+}
+
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certificaterevocations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=certificaterevocations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;clusterissuers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
+
+// Reconcile signs the CRL of the issuer again when its revocations or its CA
+// changed, or when the CRL is about to reach its nextUpdate.
+func (r *CRLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+	path := res.CRLPath(req.Namespace, req.Name)
+
+	config := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	spec := crlSpec(config)
+	if spec == nil {
+		r.Store.delete(path)
+		return ctrl.Result{}, nil
+	}
+	validity, refreshBefore := crlTimings(spec)
+
+	issuer, secretNamespace, err := caIssuer(ctx, r.Client, config, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if issuer == nil {
+		// the ConfigMap is garbage collected with the issuer
+		reqLogger.V(2).Info("Not a CA issuer")
+		r.Store.delete(path)
+		return ctrl.Result{}, nil
+	}
+
+	ca, err := readCA(ctx, r.Client, r.Reader, secretNamespace, issuer.GetSpec().CA.SecretName)
+	if err != nil {
+		reqLogger.Error(err, "Cannot read the CA of the issuer")
+		r.Recorder.Event(issuer, corev1.EventTypeWarning, "CRLFailed", err.Error())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	allowed := clusterRevocationNamespaces(r.NS, r.ClusterRevocationNamespaces)
+	revocations, refused, err := listRevocations(ctx, r.Client, issuer, allowed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range refused {
+		message := fmt.Sprintf("The revocations of ClusterIssuer %s are only honoured from the namespaces %s",
+			issuer.GetName(), strings.Join(allowed, ", "))
+		if err := r.updateRevocationStatus(ctx, &refused[i], nil, metav1.ConditionFalse, "NamespaceNotAllowed", message); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	now := time.Now()
+	var revoked []revokedCertificate
+	published := map[types.NamespacedName]revokedCertificate{}
+	for i := range revocations {
+		revocation := &revocations[i]
+		entry, err := resolve(ctx, r.Client, r.Reader, revocation, now)
+		if err != nil {
+			if err := r.updateRevocationStatus(ctx, revocation, nil, metav1.ConditionFalse, "SerialUnknown", err.Error()); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		revoked = append(revoked, entry)
+		published[client.ObjectKeyFromObject(revocation)] = entry
+	}
+	sortRevoked(revoked)
+
+	nextUpdate, err := r.publish(ctx, issuer, secretNamespace, ca, revoked, now, validity, refreshBefore)
+	if err != nil {
+		r.Recorder.Event(issuer, corev1.EventTypeWarning, "CRLFailed", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	for i := range revocations {
+		revocation := &revocations[i]
+		entry, ok := published[client.ObjectKeyFromObject(revocation)]
+		if !ok {
+			continue
+		}
+		message := fmt.Sprintf("Listed in the CRL of %s %s", issuer.GetObjectKind().GroupVersionKind().Kind, issuer.GetName())
+		if err := r.updateRevocationStatus(ctx, revocation, &entry, metav1.ConditionTrue, "Published", message); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	requeueAfter := time.Until(nextUpdate.Add(-refreshBefore))
+	if requeueAfter < minCRLRequeue {
+		requeueAfter = minCRLRequeue
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// crlTimings returns the validity of the CRLs and how long before its
+// nextUpdate a CRL is signed again. A refreshBefore that is not shorter than
+// the validity would have the CRL signed again on every reconcile, a third of
+// the validity is used instead.
+func crlTimings(spec *operatorv1.CRLSpec) (time.Duration, time.Duration) {
+	validity, refreshBefore := res.DefaultCRLValidity, res.DefaultCRLRefreshBefore
+	if spec.Validity != nil && spec.Validity.Duration > 0 {
+		validity = spec.Validity.Duration
+	}
+	if spec.RefreshBefore != nil {
+		refreshBefore = spec.RefreshBefore.Duration
+	}
+	if refreshBefore < 0 || refreshBefore >= validity {
+		logd.V(1).Info("CRL refreshBefore out of range, using a third of the validity", "validity", validity, "refreshBefore", refreshBefore)
+		refreshBefore = validity / 3
+	}
+	return validity, refreshBefore
+}
+
+// crlSpec returns the CRL configuration, or nil when the CRLs are disabled
+func crlSpec(config *operatorv1.CertManagerConfig) *operatorv1.CRLSpec {
+	if config.Spec.Revocation == nil || config.Spec.Revocation.CRL == nil || !config.Spec.Revocation.CRL.Enabled {
+		return nil
+	}
+	return config.Spec.Revocation.CRL
+}
+
+// publish writes the CRL to its ConfigMap when it changed or is about to be
+// stale, and serves it. It returns the nextUpdate of the served CRL.
+func (r *CRLReconciler) publish(ctx context.Context, issuer certmanagerv1.GenericIssuer, namespace string, ca *signingCA,
+	revoked []revokedCertificate, now time.Time, validity, refreshBefore time.Duration) (time.Time, error) {
+	path := res.CRLPath(issuer.GetNamespace(), issuer.GetName())
+	hash := crlHash(ca.cert, revoked)
+
+	cm := &corev1.ConfigMap{}
+	err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: crlConfigMapName(issuer)}, cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return time.Time{}, err
+	}
+	exists := err == nil
+
+	number := big.NewInt(1)
+	if exists {
+		if current, err := x509.ParseRevocationList(cm.BinaryData[res.CRLKey]); err == nil {
+			if cm.Annotations[res.CRLHashAnnotation] == hash && now.Before(current.NextUpdate.Add(-refreshBefore)) {
+				r.Store.set(path, cm.BinaryData[res.CRLKey])
+				return current.NextUpdate, nil
+			}
+			if current.Number != nil {
+				number.Add(current.Number, big.NewInt(1))
+			}
+		}
+	}
+
+	crl, err := buildCRL(ca, revoked, number, now, validity)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot sign the CRL of %s: %v", issuer.GetName(), err)
+	}
+
+	if !exists {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: crlConfigMapName(issuer), Namespace: namespace}}
+	}
+	cm.Labels = map[string]string{res.CRLIssuerLabel: issuer.GetObjectKind().GroupVersionKind().Kind}
+	cm.Annotations = map[string]string{res.CRLHashAnnotation: hash}
+	cm.BinaryData = map[string][]byte{res.CRLKey: crl}
+	if err := controllerutil.SetControllerReference(issuer, cm, r.Scheme); err != nil {
+		return time.Time{}, err
+	}
+	logd.Info("Publishing CRL", "issuer", issuer.GetName(), "namespace", issuer.GetNamespace(), "number", number, "revoked", len(revoked))
+	if exists {
+		err = r.Client.Update(ctx, cm)
+	} else {
+		err = r.Client.Create(ctx, cm)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	r.Store.set(path, crl)
+	return now.Add(validity), nil
+}
+
+// crlConfigMapName returns the name of the ConfigMap the CRL of the issuer is
+// published to. It differs for ClusterIssuers, whose ConfigMap lives in the
+// cluster resource namespace next to the Issuers of that namespace.
+func crlConfigMapName(issuer certmanagerv1.GenericIssuer) string {
+	if issuer.GetNamespace() == "" {
+		return issuer.GetName() + "-cluster-crl"
+	}
+	return issuer.GetName() + "-crl"
+}
+
+// updateRevocationStatus sets the Published condition of the revocation, and
+// the serial and time it was published with
+func (r *CRLReconciler) updateRevocationStatus(ctx context.Context, revocation *operatorv1.CertificateRevocation, entry *revokedCertificate,
+	status metav1.ConditionStatus, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorv1.CertificateRevocation{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(revocation), current); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		updated := current.DeepCopy()
+		if entry != nil {
+			updated.Status.SerialNumber = formatSerial(entry.serial)
+			if updated.Status.RevocationTime == nil {
+				revocationTime := metav1.NewTime(entry.revocationTime)
+				updated.Status.RevocationTime = &revocationTime
+			}
+		}
+		meta.SetStatusCondition(&updated.Status.Conditions, metav1.Condition{
+			Type:               PublishedCondition,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: current.Generation,
+		})
+		if equality.Semantic.DeepEqual(current.Status, updated.Status) {
+			return nil
+		}
+		return r.Client.Status().Update(ctx, updated)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CRLReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("crl-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.Issuer{}}, handler.EnqueueRequestsFromMapFunc(caIssuerRequest),
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.ClusterIssuer{}}, handler.EnqueueRequestsFromMapFunc(caIssuerRequest),
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertificateRevocation{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		revocation, ok := obj.(*operatorv1.CertificateRevocation)
+		if !ok {
+			return nil
+		}
+		key, ok := issuerKey(revocation)
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: key}}
+	}), predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch the CertManagerConfig to publish or drop all CRLs
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertManagerConfig{}}, handler.EnqueueRequestsFromMapFunc(r.allCAIssuers),
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch the labelled CA secrets, to sign the CRLs with a renewed CA
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.caIssuersForSecret), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+	})
+}
+
+// caIssuerRequest maps a CA Issuer or ClusterIssuer to its request
+func caIssuerRequest(obj client.Object) []reconcile.Request {
+	issuer, ok := obj.(certmanagerv1.GenericIssuer)
+	if !ok || issuer.GetSpec().CA == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}}
+}
+
+func (r *CRLReconciler) allCAIssuers(obj client.Object) []reconcile.Request {
+	return r.caIssuersForSecret(nil)
+}
+
+// caIssuersForSecret returns the CA issuers signing with the secret, or all
+// of them when secret is nil
+func (r *CRLReconciler) caIssuersForSecret(secret client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	issuerList := &certmanagerv1.IssuerList{}
+	if err := r.Client.List(context.TODO(), issuerList); err != nil {
+		logd.Error(err, "Failed to list issuers")
+		return nil
+	}
+	for i := range issuerList.Items {
+		issuer := &issuerList.Items[i]
+		if issuer.Spec.CA != nil && (secret == nil ||
+			issuer.Namespace == secret.GetNamespace() && issuer.Spec.CA.SecretName == secret.GetName()) {
+			requests = append(requests, caIssuerRequest(issuer)...)
+		}
+	}
+	clusterIssuerList := &certmanagerv1.ClusterIssuerList{}
+	if err := r.Client.List(context.TODO(), clusterIssuerList); err != nil {
+		logd.Error(err, "Failed to list cluster issuers")
+		return nil
+	}
+	for i := range clusterIssuerList.Items {
+		issuer := &clusterIssuerList.Items[i]
+		// the secret of a ClusterIssuer is in the cluster resource namespace
+		if issuer.Spec.CA != nil && (secret == nil || issuer.Spec.CA.SecretName == secret.GetName()) {
+			requests = append(requests, caIssuerRequest(issuer)...)
+		}
+	}
+	return requests
+}
@@ -0,0 +1,375 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+const testNS = "app"
+
+// newCA returns a CA keypair secret able to sign CRLs
+func newCA(t *testing.T) (*corev1.Secret, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ca-secret", Namespace: testNS},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+	return secret, cert, key
+}
+
+// newLeaf returns the secret of a certificate signed by the CA
+func newLeaf(t *testing.T, name string, serial int64, ca *x509.Certificate, caKey *ecdsa.PrivateKey) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS},
+		Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func newReconciler(t *testing.T, objs ...client.Object) *CRLReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, operatorv1.AddToScheme, certmanagerv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &CRLReconciler{
+		Client:   c,
+		Reader:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		Store:    NewStore(),
+		NS:       res.DeployNamespace,
+	}
+}
+
+func crlConfig() *operatorv1.CertManagerConfig {
+	return &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Revocation: &operatorv1.RevocationSpec{CRL: &operatorv1.CRLSpec{Enabled: true}},
+		},
+	}
+}
+
+func revocation(name string, spec operatorv1.CertificateRevocationSpec) *operatorv1.CertificateRevocation {
+	spec.IssuerRef = cmmeta.ObjectReference{Name: "test-ca-issuer", Kind: certmanagerv1.IssuerKind}
+	return &operatorv1.CertificateRevocation{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNS}, Spec: spec}
+}
+
+// servedCRL fetches the CRL of the issuer from the revocation server
+func servedCRL(t *testing.T, r *CRLReconciler, ca *x509.Certificate) *x509.RevocationList {
+	t.Helper()
+	server := httptest.NewServer(r.Store)
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL + res.CRLPath(testNS, "test-ca-issuer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	der, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := crl.CheckSignatureFrom(ca); err != nil {
+		t.Fatalf("CRL not signed by the CA: %v", err)
+	}
+	return crl
+}
+
+func TestReconcilePublishesCRL(t *testing.T) {
+	caSecret, ca, caKey := newCA(t)
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ca-issuer", Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: caSecret.Name},
+		}},
+	}
+	leafSecret := newLeaf(t, "leaf-tls", 0x1234, ca, caKey)
+	certificate := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf", Namespace: testNS},
+		Spec:       certmanagerv1.CertificateSpec{SecretName: leafSecret.Name},
+	}
+	bySerial := revocation("by-serial", operatorv1.CertificateRevocationSpec{SerialNumber: "0a:bc", Reason: "keyCompromise"})
+	byCertificate := revocation("by-certificate", operatorv1.CertificateRevocationSpec{CertificateName: "leaf", Reason: "superseded"})
+	unresolved := revocation("unresolved", operatorv1.CertificateRevocationSpec{CertificateName: "missing"})
+	r := newReconciler(t, crlConfig(), caSecret, issuer, leafSecret, certificate, bySerial, byCertificate, unresolved)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: issuer.Name}}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := res.DefaultCRLValidity - res.DefaultCRLRefreshBefore; result.RequeueAfter <= 0 || result.RequeueAfter > want {
+		t.Errorf("got requeue after %s, want about %s", result.RequeueAfter, want)
+	}
+
+	crl := servedCRL(t, r, ca)
+	if crl.Number.Int64() != 1 {
+		t.Errorf("got CRL number %d", crl.Number)
+	}
+	serials := map[string]bool{}
+	for _, entry := range crl.RevokedCertificates {
+		serials[entry.SerialNumber.Text(16)] = true
+	}
+	if len(serials) != 2 || !serials["abc"] || !serials["1234"] {
+		t.Errorf("got revoked serials %v", serials)
+	}
+
+	for name, want := range map[string]string{"by-serial": "abc", "by-certificate": "1234"} {
+		got := &operatorv1.CertificateRevocation{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: name}, got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Status.SerialNumber != want || got.Status.RevocationTime == nil || !meta.IsStatusConditionTrue(got.Status.Conditions, PublishedCondition) {
+			t.Errorf("unexpected status of %s: %+v", name, got.Status)
+		}
+	}
+	got := &operatorv1.CertificateRevocation{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(unresolved), got); err != nil {
+		t.Fatal(err)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, PublishedCondition); cond == nil || cond.Reason != "SerialUnknown" {
+		t.Errorf("unexpected condition of an unresolved revocation %+v", cond)
+	}
+
+	// the CA secret is watched for renewals
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(caSecret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("CA secret not labelled")
+	}
+
+	// an unchanged CRL is not signed again, even after a restart
+	r.Store = NewStore()
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if crl := servedCRL(t, r, ca); crl.Number.Int64() != 1 {
+		t.Errorf("unchanged CRL signed again with number %d", crl.Number)
+	}
+
+	// a new revocation is published with the next number
+	if err := r.Client.Create(ctx, revocation("another", operatorv1.CertificateRevocationSpec{SerialNumber: "ff"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	crl = servedCRL(t, r, ca)
+	if crl.Number.Int64() != 2 || len(crl.RevokedCertificates) != 3 {
+		t.Errorf("got CRL number %d with %d entries", crl.Number, len(crl.RevokedCertificates))
+	}
+}
+
+func TestReconcileDropsDisabledCRL(t *testing.T) {
+	caSecret, _, _ := newCA(t)
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ca-issuer", Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: caSecret.Name},
+		}},
+	}
+	config := crlConfig()
+	r := newReconciler(t, config, caSecret, issuer)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(issuer)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	path := res.CRLPath(testNS, issuer.Name)
+	if _, ok := r.Store.get(path); !ok {
+		t.Fatal("CRL not served")
+	}
+
+	config.Spec.Revocation.CRL.Enabled = false
+	if err := r.Client.Update(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Store.get(path); ok {
+		t.Errorf("CRL still served once disabled")
+	}
+}
+
+func TestClusterIssuerRevocationNamespaces(t *testing.T) {
+	issuer := &certmanagerv1.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "cluster-ca"}}
+	clusterRevocation := func(namespace string) *operatorv1.CertificateRevocation {
+		return &operatorv1.CertificateRevocation{
+			ObjectMeta: metav1.ObjectMeta{Name: "revoke", Namespace: namespace},
+			Spec: operatorv1.CertificateRevocationSpec{
+				IssuerRef:    cmmeta.ObjectReference{Name: issuer.Name, Kind: certmanagerv1.ClusterIssuerKind},
+				SerialNumber: "01",
+			},
+		}
+	}
+	r := newReconciler(t, issuer, clusterRevocation(res.DeployNamespace), clusterRevocation("security"), clusterRevocation("tenant"))
+	r.ClusterRevocationNamespaces = []string{"security"}
+
+	revocations, refused, err := listRevocations(context.TODO(), r.Client, issuer, clusterRevocationNamespaces(r.NS, r.ClusterRevocationNamespaces))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var honoured []string
+	for _, revocation := range revocations {
+		honoured = append(honoured, revocation.Namespace)
+	}
+	if len(honoured) != 2 || !containsString(honoured, res.DeployNamespace) || !containsString(honoured, "security") {
+		t.Errorf("got revocations honoured from %v", honoured)
+	}
+	if len(refused) != 1 || refused[0].Namespace != "tenant" {
+		t.Errorf("got refused revocations %v", refused)
+	}
+}
+
+func TestReconcileCRLRefreshBeforeOutOfRange(t *testing.T) {
+	caSecret, ca, _ := newCA(t)
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ca-issuer", Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: caSecret.Name},
+		}},
+	}
+	config := crlConfig()
+	config.Spec.Revocation.CRL.Validity = &metav1.Duration{Duration: time.Hour}
+	config.Spec.Revocation.CRL.RefreshBefore = &metav1.Duration{Duration: 2 * time.Hour}
+	r := newReconciler(t, config, caSecret, issuer)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNS, Name: issuer.Name}}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a third of the validity is used to refresh the CRL
+	if want := 40 * time.Minute; result.RequeueAfter < minCRLRequeue || result.RequeueAfter > want {
+		t.Errorf("got requeue after %s, want about %s", result.RequeueAfter, want)
+	}
+
+	// the CRL is not signed again on every reconcile
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if crl := servedCRL(t, r, ca); crl.Number.Int64() != 1 {
+		t.Errorf("fresh CRL signed again with number %d", crl.Number)
+	}
+}
+
+func TestCRLTimings(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+	for _, tc := range []struct {
+		name                    string
+		spec                    operatorv1.CRLSpec
+		validity, refreshBefore time.Duration
+	}{
+		{"defaults", operatorv1.CRLSpec{}, res.DefaultCRLValidity, res.DefaultCRLRefreshBefore},
+		{"in range", operatorv1.CRLSpec{Validity: duration(6 * time.Hour), RefreshBefore: duration(time.Hour)}, 6 * time.Hour, time.Hour},
+		{"equal to the validity", operatorv1.CRLSpec{Validity: duration(6 * time.Hour), RefreshBefore: duration(6 * time.Hour)}, 6 * time.Hour, 2 * time.Hour},
+		{"default refresh longer than the validity", operatorv1.CRLSpec{Validity: duration(3 * time.Hour)}, 3 * time.Hour, time.Hour},
+		{"negative", operatorv1.CRLSpec{RefreshBefore: duration(-time.Hour)}, res.DefaultCRLValidity, res.DefaultCRLValidity / 3},
+	} {
+		validity, refreshBefore := crlTimings(&tc.spec)
+		if validity != tc.validity || refreshBefore != tc.refreshBefore {
+			t.Errorf("%s: got %s/%s, want %s/%s", tc.name, validity, refreshBefore, tc.validity, tc.refreshBefore)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// maxOCSPRequestSize bounds the body of the OCSP requests, which hold a
+// handful of hashes and a serial
+const maxOCSPRequestSize = 10 * 1024
+
+// responderState is what the responder answers with
+type responderState struct {
+	ca       *x509.Certificate
+	signer   *signingCA
+	revoked  map[string]revokedCertificate
+	validity time.Duration
+}
+
+// Responder answers the OCSP requests for the certificates of one CA, signing
+// the responses with a delegated OCSP signing certificate. Serials not revoked
+// are reported as good, as the responder does not track the certificates
+// issued by the CA.
+type Responder struct {
+	mu    sync.RWMutex
+	state *responderState
+}
+
+// NewResponder returns a Responder that answers nothing until the OCSP
+// controller configures it
+func NewResponder() *Responder {
+	return &Responder{}
+}
+
+func (r *Responder) set(state *responderState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = state
+}
+
+func (r *Responder) get() *responderState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// ServeHTTP answers an OCSP request sent with POST, or with GET as the
+// base64 encoding of the request appended to the path
+func (r *Responder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	state := r.get()
+	if state == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	var der []byte
+	switch req.Method {
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxOCSPRequestSize))
+		if err != nil {
+			writeOCSP(w, ocsp.MalformedRequestErrorResponse, 0)
+			return
+		}
+		der = body
+	case http.MethodGet:
+		encoded, err := url.PathUnescape(strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, res.OCSPPath), "/"))
+		if err == nil {
+			der, err = base64.StdEncoding.DecodeString(encoded)
+		}
+		if err != nil {
+			writeOCSP(w, ocsp.MalformedRequestErrorResponse, 0)
+			return
+		}
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	response, err := state.respond(der, time.Now())
+	if err != nil {
+		logd.V(2).Info("Rejecting OCSP request", "reason", err.Error())
+		writeOCSP(w, response, 0)
+		return
+	}
+	writeOCSP(w, response, state.validity)
+}
+
+func writeOCSP(w http.ResponseWriter, response []byte, maxAge time.Duration) {
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	if maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d, public, no-transform, must-revalidate", int(maxAge.Seconds())))
+	}
+	_, _ = w.Write(response)
+}
+
+// respond returns the signed response to the DER encoded request. When the
+// request cannot be answered, it returns the OCSP error response to send
+// along with the reason.
+func (s *responderState) respond(der []byte, now time.Time) ([]byte, error) {
+	req, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return ocsp.MalformedRequestErrorResponse, err
+	}
+	if !s.issuedBy(req) {
+		return ocsp.UnauthorizedErrorResponse, fmt.Errorf("request for serial %s of another CA", formatSerial(req.SerialNumber))
+	}
+
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: req.SerialNumber,
+		ThisUpdate:   now.UTC(),
+		NextUpdate:   now.Add(s.validity).UTC(),
+		Certificate:  s.signer.cert,
+	}
+	if entry, ok := s.revoked[formatSerial(req.SerialNumber)]; ok {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = entry.revocationTime.UTC()
+		template.RevocationReason = reasonCodes[entry.reason]
+	}
+	response, err := ocsp.CreateResponse(s.ca, s.signer.cert, template, s.signer.key)
+	if err != nil {
+		return ocsp.InternalErrorErrorResponse, err
+	}
+	return response, nil
+}
+
+// issuedBy returns whether the request is for a certificate of the CA, by
+// the hashes of its name and key
+func (s *responderState) issuedBy(req *ocsp.Request) bool {
+	if !req.HashAlgorithm.Available() {
+		return false
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(s.ca.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return false
+	}
+	return bytes.Equal(hash(req.HashAlgorithm, s.ca.RawSubject), req.IssuerNameHash) &&
+		bytes.Equal(hash(req.HashAlgorithm, spki.PublicKey.RightAlign()), req.IssuerKeyHash)
+}
+
+func hash(algorithm crypto.Hash, data []byte) []byte {
+	h := algorithm.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
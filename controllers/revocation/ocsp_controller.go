@@ -0,0 +1,318 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	cmmeta "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// ocspResyncInterval is how often the revoked serials are read again, since
+// the ConfigMap listing them is not watched
+const ocspResyncInterval = 5 * time.Minute
+
+// OCSPReconciler configures the OCSP Responder from the CertManagerConfig:
+// the CA issuer it answers for, the delegated OCSP signing certificate issued
+// from that CA, and the serials revoked by the CertificateRevocations of the
+// issuer and by the revoked serials ConfigMap. The only request is the
+// default CertManagerConfig.
+type OCSPReconciler struct {
+	Client    client.Client
+	Reader    client.Reader
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Responder *Responder
+	NS        string
+	// ClusterRevocationNamespaces are the namespaces, besides NS, the
+	// revocations of ClusterIssuers are honoured from
+	ClusterRevocationNamespaces []string
+}
+
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;delete
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// Reconcile requests the OCSP signing certificate and loads the responder
+// once it is issued, or stops the responder when OCSP is disabled.
+func (r *OCSPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Name", req.Name)
+
+	config := &operatorv1.CertManagerConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: res.CertManagerInstanceName}, config); err != nil {
+		if errors.IsNotFound(err) {
+			// the signing certificate is garbage collected with the CertManagerConfig
+			r.Responder.set(nil)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	spec := ocspSpec(config)
+	if spec == nil {
+		r.Responder.set(nil)
+		return ctrl.Result{}, r.deleteSigner(ctx)
+	}
+	validity := res.DefaultOCSPResponseValidity
+	if spec.ResponseValidity != nil {
+		validity = spec.ResponseValidity.Duration
+	}
+
+	ref := ocspIssuerRef(spec)
+	if ref.Group != certmanagerv1.SchemeGroupVersion.Group {
+		r.Responder.set(nil)
+		r.Recorder.Eventf(config, corev1.EventTypeWarning, "OCSPFailed", "Issuers of group %s are not supported", ref.Group)
+		return ctrl.Result{}, nil
+	}
+	key := types.NamespacedName{Namespace: r.NS, Name: ref.Name}
+	if ref.Kind == certmanagerv1.ClusterIssuerKind {
+		key.Namespace = ""
+	}
+	issuer, secretNamespace, err := caIssuer(ctx, r.Client, config, key)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if issuer == nil {
+		r.Responder.set(nil)
+		r.Recorder.Eventf(config, corev1.EventTypeWarning, "OCSPFailed", "%s %s is not a CA issuer", ref.Kind, ref.Name)
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	ca, err := readCA(ctx, r.Client, r.Reader, secretNamespace, issuer.GetSpec().CA.SecretName)
+	if err != nil {
+		reqLogger.Error(err, "Cannot read the CA of the issuer")
+		r.Responder.set(nil)
+		r.Recorder.Event(config, corev1.EventTypeWarning, "OCSPFailed", err.Error())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	if err := r.applySigner(ctx, config, ref); err != nil {
+		return ctrl.Result{}, err
+	}
+	signer, err := readCA(ctx, r.Client, r.Reader, r.NS, res.OCSPSignerSecretName)
+	if errors.IsNotFound(err) {
+		reqLogger.Info("Waiting for the OCSP signing certificate to be issued")
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	} else if err != nil {
+		r.Responder.set(nil)
+		r.Recorder.Event(config, corev1.EventTypeWarning, "OCSPFailed", err.Error())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	if err := signer.cert.CheckSignatureFrom(ca.cert); err != nil {
+		// the CA was renewed or replaced, and cert-manager issues the signing
+		// certificate again once its secret is gone
+		reqLogger.Info("Re-issuing the OCSP signing certificate from the current CA")
+		r.Responder.set(nil)
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: res.OCSPSignerSecretName, Namespace: r.NS}}
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, client.IgnoreNotFound(r.Client.Delete(ctx, secret))
+	}
+
+	revoked, err := r.revokedSerials(ctx, config, issuer, spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	reqLogger.V(2).Info("Loading OCSP responder", "issuer", issuer.GetName(), "revoked", len(revoked))
+	r.Responder.set(&responderState{ca: ca.cert, signer: signer, revoked: revoked, validity: validity})
+	return ctrl.Result{RequeueAfter: ocspResyncInterval}, nil
+}
+
+// ocspSpec returns the OCSP configuration, or nil when OCSP is disabled
+func ocspSpec(config *operatorv1.CertManagerConfig) *operatorv1.OCSPSpec {
+	if config.Spec.Revocation == nil || config.Spec.Revocation.OCSP == nil || !config.Spec.Revocation.OCSP.Enabled {
+		return nil
+	}
+	return config.Spec.Revocation.OCSP
+}
+
+// ocspIssuerRef returns the issuer the responder answers for, with its kind
+// and group defaulted
+func ocspIssuerRef(spec *operatorv1.OCSPSpec) cmmeta.ObjectReference {
+	ref := cmmeta.ObjectReference{Name: res.CSCAIssuerName}
+	if spec.IssuerRef != nil {
+		ref = *spec.IssuerRef
+	}
+	if ref.Kind == "" {
+		ref.Kind = certmanagerv1.IssuerKind
+	}
+	if ref.Group == "" {
+		ref.Group = certmanagerv1.SchemeGroupVersion.Group
+	}
+	return ref
+}
+
+// applySigner creates the OCSP signing Certificate, or updates it when it
+// does not match the issuer of the responder
+func (r *OCSPReconciler) applySigner(ctx context.Context, config *operatorv1.CertManagerConfig, ref cmmeta.ObjectReference) error {
+	cert := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: res.OCSPSignerCertName, Namespace: r.NS},
+		Spec: certmanagerv1.CertificateSpec{
+			CommonName: res.OCSPSignerCertName,
+			SecretName: res.OCSPSignerSecretName,
+			IssuerRef:  ref,
+			Usages:     []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageOCSPSigning},
+		},
+	}
+	if err := controllerutil.SetControllerReference(config, cert, r.Scheme); err != nil {
+		return err
+	}
+
+	existing := &certmanagerv1.Certificate{}
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(cert), existing)
+	if errors.IsNotFound(err) {
+		logd.Info("Creating OCSP signing certificate", "issuer", ref.Name)
+		return r.Client.Create(ctx, cert)
+	} else if err != nil {
+		return err
+	}
+
+	old := existing.DeepCopy()
+	existing.Spec.CommonName = cert.Spec.CommonName
+	existing.Spec.SecretName = cert.Spec.SecretName
+	existing.Spec.IssuerRef = cert.Spec.IssuerRef
+	existing.Spec.Usages = cert.Spec.Usages
+	if equality.Semantic.DeepEqual(old, existing) {
+		return nil
+	}
+	logd.Info("Updating OCSP signing certificate", "issuer", ref.Name)
+	return r.Client.Update(ctx, existing)
+}
+
+// deleteSigner deletes the OCSP signing Certificate and its secret
+func (r *OCSPReconciler) deleteSigner(ctx context.Context) error {
+	cert := &certmanagerv1.Certificate{ObjectMeta: metav1.ObjectMeta{Name: res.OCSPSignerCertName, Namespace: r.NS}}
+	if err := r.Client.Delete(ctx, cert); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	} else {
+		logd.Info("Deleting OCSP signing certificate")
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: res.OCSPSignerSecretName, Namespace: r.NS}}
+	return client.IgnoreNotFound(r.Client.Delete(ctx, secret))
+}
+
+// revokedSerials returns the certificates revoked by the CertificateRevocations
+// of the issuer and by the revoked serials ConfigMap, by their serial
+func (r *OCSPReconciler) revokedSerials(ctx context.Context, config *operatorv1.CertManagerConfig, issuer certmanagerv1.GenericIssuer,
+	spec *operatorv1.OCSPSpec) (map[string]revokedCertificate, error) {
+	revoked := map[string]revokedCertificate{}
+
+	// the CRL controller reports the revocations refused
+	revocations, _, err := listRevocations(ctx, r.Client, issuer, clusterRevocationNamespaces(r.NS, r.ClusterRevocationNamespaces))
+	if err != nil {
+		return nil, err
+	}
+	for i := range revocations {
+		revocation := &revocations[i]
+		// the CRL controller reports the revocations it cannot resolve
+		entry, err := resolve(ctx, r.Client, r.Reader, revocation, revocation.CreationTimestamp.Time)
+		if err != nil {
+			logd.V(2).Info("Skipping revocation", "namespace", revocation.Namespace, "name", revocation.Name, "reason", err.Error())
+			continue
+		}
+		revoked[formatSerial(entry.serial)] = entry
+	}
+
+	if spec.RevokedSerialsConfigMap == "" {
+		return revoked, nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: r.NS, Name: spec.RevokedSerialsConfigMap}, cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		r.Recorder.Eventf(config, corev1.EventTypeWarning, "OCSPFailed", "ConfigMap %s of the revoked serials not found", spec.RevokedSerialsConfigMap)
+		return revoked, nil
+	}
+	for _, line := range strings.Split(cm.Data[res.OCSPRevokedSerialsKey], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		serial, err := parseSerial(line)
+		if err != nil {
+			logd.Info("Skipping invalid revoked serial", "configmap", cm.Name, "serial", line)
+			continue
+		}
+		// a CertificateRevocation of the same serial also has its reason
+		if _, ok := revoked[formatSerial(serial)]; !ok {
+			revoked[formatSerial(serial)] = revokedCertificate{serial: serial, revocationTime: cm.CreationTimestamp.Time, reason: "unspecified"}
+		}
+	}
+	return revoked, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OCSPReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("ocsp-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	toDefault := handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}}
+	})
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertManagerConfig{}}, toDefault, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &operatorv1.CertificateRevocation{}}, toDefault, predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch the signing certificate, to restore it when it is changed
+	err = c.Watch(&source.Kind{Type: &certmanagerv1.Certificate{}}, toDefault, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == r.NS && obj.GetName() == res.OCSPSignerCertName
+	}))
+	if err != nil {
+		return err
+	}
+
+	// Watch the labelled secrets, to load a renewed CA or signing certificate
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, toDefault, predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+	})
+}
@@ -0,0 +1,217 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// newSigner returns the secret of a delegated OCSP signing certificate
+// issued by the CA
+func newSigner(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: res.OCSPSignerCertName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.OCSPSignerSecretName, Namespace: testNS},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func newOCSPReconciler(t *testing.T, objs ...client.Object) *OCSPReconciler {
+	t.Helper()
+	crl := newReconciler(t, objs...)
+	return &OCSPReconciler{
+		Client:    crl.Client,
+		Reader:    crl.Reader,
+		Scheme:    crl.Scheme,
+		Recorder:  crl.Recorder,
+		Responder: NewResponder(),
+		NS:        testNS,
+	}
+}
+
+// queryOCSP asks the responder for the status of a serial of the CA
+func queryOCSP(t *testing.T, url string, serial int64, ca *x509.Certificate) (*ocsp.Response, error) {
+	t.Helper()
+	der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: big.NewInt(serial)}, ca, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url+res.OCSPPath, "application/ocsp-request", bytes.NewReader(der))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ocsp.ParseResponse(body, ca)
+}
+
+func TestReconcileServesOCSP(t *testing.T) {
+	caSecret, ca, caKey := newCA(t)
+	issuer := &certmanagerv1.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCAIssuerName, Namespace: testNS},
+		Spec: certmanagerv1.IssuerSpec{IssuerConfig: certmanagerv1.IssuerConfig{
+			CA: &certmanagerv1.CAIssuer{SecretName: caSecret.Name},
+		}},
+	}
+	config := &operatorv1.CertManagerConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CertManagerInstanceName},
+		Spec: operatorv1.CertManagerConfigSpec{
+			Revocation: &operatorv1.RevocationSpec{OCSP: &operatorv1.OCSPSpec{Enabled: true, RevokedSerialsConfigMap: "revoked"}},
+		},
+	}
+	serials := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "revoked", Namespace: testNS},
+		Data:       map[string]string{res.OCSPRevokedSerialsKey: "# retired\n20\nnot-a-serial\n"},
+	}
+	revoked := revocation("revoked-10", operatorv1.CertificateRevocationSpec{SerialNumber: "10", Reason: "keyCompromise"})
+	revoked.Spec.IssuerRef.Name = res.CSCAIssuerName
+	r := newOCSPReconciler(t, config, issuer, caSecret, serials, revoked)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: res.CertManagerInstanceName}}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("no requeue while waiting for the signing certificate")
+	}
+	signerCert := &certmanagerv1.Certificate{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: testNS, Name: res.OCSPSignerCertName}, signerCert); err != nil {
+		t.Fatal(err)
+	}
+	if signerCert.Spec.IssuerRef.Name != res.CSCAIssuerName || signerCert.Spec.SecretName != res.OCSPSignerSecretName {
+		t.Errorf("unexpected signing certificate %+v", signerCert.Spec)
+	}
+
+	if err := r.Client.Create(ctx, newSigner(t, ca, caKey)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(r.Responder)
+	defer server.Close()
+	for serial, want := range map[int64]int{0x10: ocsp.Revoked, 0x20: ocsp.Revoked, 0x30: ocsp.Good} {
+		resp, err := queryOCSP(t, server.URL, serial, ca)
+		if err != nil {
+			t.Fatalf("serial %x: %v", serial, err)
+		}
+		if resp.Status != want {
+			t.Errorf("serial %x: got status %d, want %d", serial, resp.Status, want)
+		}
+		if resp.Certificate == nil || resp.Certificate.Subject.CommonName != res.OCSPSignerCertName {
+			t.Errorf("serial %x: response not signed by the delegated signer", serial)
+		}
+		if serial == 0x10 && resp.RevocationReason != ocsp.KeyCompromise {
+			t.Errorf("got revocation reason %d, want %d", resp.RevocationReason, ocsp.KeyCompromise)
+		}
+	}
+
+	// GET requests carry the request in the path
+	der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: big.NewInt(0x10)}, ca, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(server.URL + res.OCSPPath + "/" + base64.StdEncoding.EncodeToString(der))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if parsed, err := ocsp.ParseResponse(body, ca); err != nil || parsed.Status != ocsp.Revoked {
+		t.Errorf("unexpected GET response %+v: %v", parsed, err)
+	}
+
+	// the certificates of another CA are not answered for
+	_, otherCA, _ := newCA(t)
+	if _, err := queryOCSP(t, server.URL, 0x10, otherCA); err != (ocsp.ResponseError{Status: ocsp.Unauthorized}) {
+		t.Errorf("got %v for a certificate of another CA, want unauthorized", err)
+	}
+
+	config.Spec.Revocation.OCSP.Enabled = false
+	if err := r.Client.Update(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(signerCert), &certmanagerv1.Certificate{}); !errors.IsNotFound(err) {
+		t.Errorf("signing certificate not deleted once OCSP is disabled: %v", err)
+	}
+	resp, err = http.Get(server.URL + res.OCSPPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d once OCSP is disabled, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,192 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// caIssuer returns the CA issuer of the request and the namespace of its CA
+// secret, or nil when there is no such CA issuer
+func caIssuer(ctx context.Context, c client.Client, config *operatorv1.CertManagerConfig, key types.NamespacedName) (certmanagerv1.GenericIssuer, string, error) {
+	var issuer certmanagerv1.GenericIssuer
+	secretNamespace := key.Namespace
+	if key.Namespace == "" {
+		issuer = &certmanagerv1.ClusterIssuer{}
+		secretNamespace = res.DeployNamespace
+		if config.Spec.ResourceNS != "" {
+			secretNamespace = config.Spec.ResourceNS
+		}
+	} else {
+		issuer = &certmanagerv1.Issuer{}
+	}
+	if err := c.Get(ctx, key, issuer); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	if issuer.GetSpec().CA == nil || !issuer.GetDeletionTimestamp().IsZero() {
+		return nil, "", nil
+	}
+	// the GVK is not set on typed objects read from the client
+	kind := certmanagerv1.IssuerKind
+	if key.Namespace == "" {
+		kind = certmanagerv1.ClusterIssuerKind
+	}
+	issuer.GetObjectKind().SetGroupVersionKind(certmanagerv1.SchemeGroupVersion.WithKind(kind))
+	return issuer, secretNamespace, nil
+}
+
+// readCA reads the keypair of the CA issuer, and labels its secret to get its
+// renewals
+func readCA(ctx context.Context, c client.Client, reader client.Reader, namespace, name string) (*signingCA, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, err
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[res.SecretWatchLabel] = ""
+		if err := c.Update(ctx, secret); err != nil {
+			return nil, err
+		}
+	}
+	return loadSigningCA(secret)
+}
+
+// listRevocations lists the CertificateRevocations of the issuer. Anyone
+// allowed to revoke in a namespace could revoke the certificates of a
+// ClusterIssuer issued to other namespaces, so the revocations of a
+// ClusterIssuer are only honoured from the allowed namespaces, and the others
+// are returned as refused.
+func listRevocations(ctx context.Context, c client.Client, issuer certmanagerv1.GenericIssuer, allowed []string) (revocations, refused []operatorv1.CertificateRevocation, err error) {
+	revocationList := &operatorv1.CertificateRevocationList{}
+	if err := c.List(ctx, revocationList, client.InNamespace(issuer.GetNamespace())); err != nil {
+		return nil, nil, err
+	}
+	for _, revocation := range revocationList.Items {
+		key, ok := issuerKey(&revocation)
+		if !ok || key != (types.NamespacedName{Namespace: issuer.GetNamespace(), Name: issuer.GetName()}) {
+			continue
+		}
+		if issuer.GetNamespace() == "" && !containsString(allowed, revocation.Namespace) {
+			refused = append(refused, revocation)
+			continue
+		}
+		revocations = append(revocations, revocation)
+	}
+	return revocations, refused, nil
+}
+
+// clusterRevocationNamespaces returns the namespaces the revocations of
+// ClusterIssuers are honoured from, the namespace of the operator and the
+// ones configured
+func clusterRevocationNamespaces(ns string, configured []string) []string {
+	return append([]string{ns}, configured...)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// issuerKey returns the request of the issuer of the revocation, and false
+// when the revocation is not for a cert-manager Issuer or ClusterIssuer
+func issuerKey(revocation *operatorv1.CertificateRevocation) (types.NamespacedName, bool) {
+	ref := revocation.Spec.IssuerRef
+	if ref.Group != "" && ref.Group != certmanagerv1.SchemeGroupVersion.Group {
+		return types.NamespacedName{}, false
+	}
+	switch ref.Kind {
+	case certmanagerv1.ClusterIssuerKind:
+		return types.NamespacedName{Name: ref.Name}, true
+	case "", certmanagerv1.IssuerKind:
+		return types.NamespacedName{Namespace: revocation.Namespace, Name: ref.Name}, true
+	}
+	return types.NamespacedName{}, false
+}
+
+// resolve returns the revoked certificate of the revocation. The serial of a
+// Certificate is read from its secret the first time only, since the
+// certificate is renewed afterwards.
+func resolve(ctx context.Context, c client.Client, reader client.Reader, revocation *operatorv1.CertificateRevocation, now time.Time) (revokedCertificate, error) {
+	entry := revokedCertificate{revocationTime: now, reason: revocation.Spec.Reason}
+	if revocation.Status.RevocationTime != nil {
+		entry.revocationTime = revocation.Status.RevocationTime.Time
+	}
+
+	serial := revocation.Status.SerialNumber
+	if serial == "" {
+		serial = revocation.Spec.SerialNumber
+	}
+	if serial == "" && revocation.Spec.CertificateName != "" {
+		cert, err := certificateSerial(ctx, c, reader, revocation.Namespace, revocation.Spec.CertificateName)
+		if err != nil {
+			return entry, err
+		}
+		serial = cert
+	}
+	if serial == "" {
+		return entry, fmt.Errorf("one of serialNumber or certificateName is required")
+	}
+
+	var err error
+	entry.serial, err = parseSerial(serial)
+	return entry, err
+}
+
+// certificateSerial returns the serial of the current certificate of a
+// Certificate
+func certificateSerial(ctx context.Context, c client.Client, reader client.Reader, namespace, name string) (string, error) {
+	certificate := &certmanagerv1.Certificate{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, certificate); err != nil {
+		return "", fmt.Errorf("cannot read certificate %s: %v", name, err)
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certificate.Spec.SecretName}, secret); err != nil {
+		return "", fmt.Errorf("cannot read secret %s of certificate %s: %v", certificate.Spec.SecretName, name, err)
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return "", fmt.Errorf("no certificate in secret %s of certificate %s", certificate.Spec.SecretName, name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	return formatSerial(cert.SerialNumber), nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package revocation
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// Store holds the published CRLs by the path they are served at
+type Store struct {
+	mu   sync.RWMutex
+	crls map[string][]byte
+}
+
+// NewStore returns an empty Store
+func NewStore() *Store {
+	return &Store{crls: map[string][]byte{}}
+}
+
+func (s *Store) set(path string, crl []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crls[path] = crl
+}
+
+func (s *Store) delete(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.crls, path)
+}
+
+func (s *Store) get(path string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	crl, ok := s.crls[path]
+	return crl, ok
+}
+
+// ServeHTTP serves the DER encoded CRL at the path of the request
+func (s *Store) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	crl, ok := s.get(req.URL.Path)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pkix-crl")
+	_, _ = w.Write(crl)
+}
+
+// Server serves the CRLs of the Store and the OCSP Responder over HTTP. Like
+// the controllers filling them, it only runs on the leader.
+type Server struct {
+	Addr      string
+	Store     *Store
+	Responder *Responder
+}
+
+// Start runs the server until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/crl/", s.Store)
+	if s.Responder != nil {
+		mux.Handle(res.OCSPPath, s.Responder)
+		mux.Handle(res.OCSPPath+"/", s.Responder)
+	}
+	server := &http.Server{Addr: s.Addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() {
+		logd.Info("Starting revocation server", "addr", s.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,312 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package secretreplicator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+var logd = log.Log.WithName("controller_secretreplicator")
+
+// SecretReplicatorReconciler copies the secrets labelled with
+// SecretWatchLabel to the namespaces listed in their
+// SecretReplicateToAnnotation, keeps the replicas in sync and deletes them
+// when the source secret or a namespace of the list is removed. It stands in
+// for the ibm-secretshare-operator when the SecretShare API is not installed.
+// Only the secrets of the namespace of the operator, and of SourceNamespaces,
+// are replicated.
+type SecretReplicatorReconciler struct {
+	Client   client.Client
+	Reader   client.Reader
+	Recorder record.EventRecorder
+	NS       string
+	// SourceNamespaces are the namespaces, besides NS, secrets are
+	// replicated from
+	SourceNamespaces []string
+}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile writes the replicas of the source secret in the request, and
+// deletes the replicas no longer wanted.
+func (r *SecretReplicatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, req.NamespacedName, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	var targets []string
+	if err == nil && secret.DeletionTimestamp.IsZero() {
+		targets = ReplicationTargets(secret)
+		// the replicas of a secret from another namespace are written with
+		// the permissions of the operator, not of who wrote the secret
+		if len(targets) > 0 && !r.allowedSource(secret.Namespace) {
+			reqLogger.Info("Not replicating a secret from a namespace not allowed")
+			r.Recorder.Event(secret, corev1.EventTypeWarning, "ReplicationRefused",
+				fmt.Sprintf("Secrets are only replicated from the namespaces %s", strings.Join(r.sourceNamespaces(), ", ")))
+			targets = nil
+		}
+	}
+
+	replicas, err := r.replicas(ctx, req.NamespacedName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	wanted := map[string]bool{}
+	for _, namespace := range targets {
+		if namespace == secret.Namespace {
+			continue
+		}
+		wanted[namespace] = true
+		if err := r.replicate(ctx, secret, namespace, replicas[namespace]); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for namespace, replica := range replicas {
+		if wanted[namespace] {
+			continue
+		}
+		reqLogger.Info("Deleting replica", "namespace", namespace)
+		if err := r.Client.Delete(ctx, replica); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// replicas returns the replicas of the source secret by namespace
+func (r *SecretReplicatorReconciler) replicas(ctx context.Context, source types.NamespacedName) (map[string]*corev1.Secret, error) {
+	secretList := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secretList, client.HasLabels{res.SecretReplicaLabel}); err != nil {
+		return nil, err
+	}
+	replicas := map[string]*corev1.Secret{}
+	for i, secret := range secretList.Items {
+		if secret.Annotations[res.SecretReplicatedFromAnnotation] == source.String() {
+			replicas[secret.Namespace] = &secretList.Items[i]
+		}
+	}
+	return replicas, nil
+}
+
+// replicate creates or updates the replica of the source secret in the
+// namespace. Secrets that are not replicas of the source are left untouched.
+func (r *SecretReplicatorReconciler) replicate(ctx context.Context, source *corev1.Secret, namespace string, replica *corev1.Secret) error {
+	if replica != nil {
+		if replica.Type != source.Type {
+			// the type of a secret is immutable, the replica is written
+			// again when its deletion is seen
+			logd.Info("Recreating replica with a new type", "namespace", namespace, "name", replica.Name)
+			if err := r.Client.Delete(ctx, replica); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
+		if reflect.DeepEqual(replica.Data, source.Data) {
+			return nil
+		}
+		replica.Data = source.Data
+		logd.Info("Updating replica", "namespace", namespace, "name", replica.Name)
+		return r.Client.Update(ctx, replica)
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			// the replica is written once the namespace is created
+			return nil
+		}
+		return err
+	}
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		return nil
+	}
+
+	// the replica has the name of the source, which may already be taken
+	// by a secret the replicator does not own
+	existing := &corev1.Secret{}
+	err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: source.Name}, existing)
+	if err == nil {
+		r.Recorder.Event(source, corev1.EventTypeWarning, "ReplicationConflict",
+			fmt.Sprintf("Not replicating to %s, secret %s/%s already exists", namespace, namespace, source.Name))
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	replica = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Namespace:   namespace,
+			Labels:      map[string]string{res.SecretWatchLabel: "", res.SecretReplicaLabel: ""},
+			Annotations: map[string]string{res.SecretReplicatedFromAnnotation: client.ObjectKeyFromObject(source).String()},
+		},
+		Type: source.Type,
+		Data: source.Data,
+	}
+	logd.Info("Creating replica", "namespace", namespace, "name", replica.Name)
+	if err := r.Client.Create(ctx, replica); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// sourceNamespaces returns the namespaces secrets are replicated from
+func (r *SecretReplicatorReconciler) sourceNamespaces() []string {
+	return append([]string{r.NS}, r.SourceNamespaces...)
+}
+
+func (r *SecretReplicatorReconciler) allowedSource(namespace string) bool {
+	for _, allowed := range r.sourceNamespaces() {
+		if namespace == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplicationTargets returns the namespaces the secret is replicated to
+func ReplicationTargets(secret *corev1.Secret) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(secret.Annotations[res.SecretReplicateToAnnotation], ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// SetReplicationTargets sets the namespaces the secret is replicated to, and
+// labels the secret so the replicator sees it
+func SetReplicationTargets(secret *corev1.Secret, namespaces []string) {
+	unique := map[string]bool{}
+	for _, namespace := range namespaces {
+		unique[namespace] = true
+	}
+	sorted := make([]string, 0, len(unique))
+	for namespace := range unique {
+		sorted = append(sorted, namespace)
+	}
+	sort.Strings(sorted)
+
+	if len(sorted) == 0 {
+		delete(secret.Annotations, res.SecretReplicateToAnnotation)
+		return
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[res.SecretReplicateToAnnotation] = strings.Join(sorted, ",")
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[res.SecretWatchLabel] = ""
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SecretReplicatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("secretreplicator-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch the sources and their replicas, skipping the updates of their
+	// metadata that don't matter to the replication
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(sourceSecret), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data) || oldSecret.Type != newSecret.Type ||
+				!reflect.DeepEqual(oldSecret.Annotations, newSecret.Annotations) ||
+				!reflect.DeepEqual(oldSecret.Labels, newSecret.Labels)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch namespaces being created, which may be waited for by a source
+	return c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.allSources), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+}
+
+// sourceSecret maps a source secret to itself and a replica to its source
+func sourceSecret(obj client.Object) []reconcile.Request {
+	annotations := obj.GetAnnotations()
+	if from, ok := annotations[res.SecretReplicatedFromAnnotation]; ok {
+		parts := strings.SplitN(from, "/", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: parts[0], Name: parts[1]}}}
+	}
+	if _, ok := annotations[res.SecretReplicateToAnnotation]; ok {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}}
+	}
+	return nil
+}
+
+func (r *SecretReplicatorReconciler) allSources(obj client.Object) []reconcile.Request {
+	secretList := &corev1.SecretList{}
+	if err := r.Client.List(context.TODO(), secretList, client.HasLabels{res.SecretWatchLabel}); err != nil {
+		logd.Error(err, "Failed to list secrets")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, secret := range secretList.Items {
+		if _, ok := secret.Annotations[res.SecretReplicateToAnnotation]; ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&secret)})
+		}
+	}
+	return requests
+}
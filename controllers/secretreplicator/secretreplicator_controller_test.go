@@ -0,0 +1,197 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package secretreplicator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func newReconciler(objs ...client.Object) *SecretReplicatorReconciler {
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objs...).Build()
+	return &SecretReplicatorReconciler{
+		Client:   c,
+		Reader:   c,
+		Recorder: record.NewFakeRecorder(100),
+		NS:       res.DeployNamespace,
+	}
+}
+
+func namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestReconcileReplicatesSecret(t *testing.T) {
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        res.CSCASecretName,
+			Namespace:   res.DeployNamespace,
+			Labels:      map[string]string{res.SecretWatchLabel: ""},
+			Annotations: map[string]string{res.SecretReplicateToAnnotation: "app-a, app-b,missing"},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	taken := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: "app-b"},
+		Data:       map[string][]byte{"other": []byte("data")},
+	}
+	r := newReconciler(source, taken, namespace(res.DeployNamespace), namespace("app-a"), namespace("app-b"))
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(source)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	replica := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: source.Name}, replica); err != nil {
+		t.Fatal(err)
+	}
+	if string(replica.Data[corev1.TLSCertKey]) != "cert" || replica.Type != corev1.SecretTypeTLS {
+		t.Errorf("unexpected replica %+v", replica)
+	}
+	if _, ok := replica.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("replica not labelled for the watch")
+	}
+	if got := sourceSecret(replica); len(got) != 1 || got[0].NamespacedName != req.NamespacedName {
+		t.Errorf("replica mapped to %v", got)
+	}
+
+	// a secret the replicator does not own is left untouched
+	got := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(taken), got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Data[corev1.TLSCertKey]; ok {
+		t.Errorf("secret not owned by the replicator overwritten")
+	}
+
+	// the replica follows the source, and goes away with its namespace in the list
+	source.Data[corev1.TLSCertKey] = []byte("renewed")
+	source.Annotations[res.SecretReplicateToAnnotation] = "app-a"
+	if err := r.Client.Update(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Create(ctx, namespace("missing")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	replica = &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: source.Name}, replica); err != nil {
+		t.Fatal(err)
+	}
+	if string(replica.Data[corev1.TLSCertKey]) != "renewed" {
+		t.Errorf("replica not updated: %q", replica.Data[corev1.TLSCertKey])
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "missing", Name: source.Name}, &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("replica written to a namespace no longer listed: %v", err)
+	}
+
+	// deleting the source deletes the replicas
+	if err := r.Client.Delete(ctx, source); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: source.Name}, &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("replica not deleted with its source: %v", err)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(taken), &corev1.Secret{}); err != nil {
+		t.Errorf("secret not owned by the replicator deleted: %v", err)
+	}
+}
+
+func TestSetReplicationTargets(t *testing.T) {
+	secret := &corev1.Secret{}
+	SetReplicationTargets(secret, []string{"b", "a", "b"})
+	if got := secret.Annotations[res.SecretReplicateToAnnotation]; got != "a,b" {
+		t.Errorf("got targets %q", got)
+	}
+	if got := ReplicationTargets(secret); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("got targets %v", got)
+	}
+	SetReplicationTargets(secret, nil)
+	if _, ok := secret.Annotations[res.SecretReplicateToAnnotation]; ok {
+		t.Errorf("annotation kept without targets")
+	}
+}
+
+func TestReconcileRefusesOtherNamespaces(t *testing.T) {
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "stolen",
+			Namespace:   "tenant",
+			Labels:      map[string]string{res.SecretWatchLabel: ""},
+			Annotations: map[string]string{res.SecretReplicateToAnnotation: "victim"},
+		},
+		Data: map[string][]byte{"key": []byte("value")},
+	}
+	// a replica written before the namespace was refused is removed
+	replica := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "stolen",
+			Namespace:   "victim",
+			Labels:      map[string]string{res.SecretWatchLabel: "", res.SecretReplicaLabel: ""},
+			Annotations: map[string]string{res.SecretReplicatedFromAnnotation: "tenant/stolen"},
+		},
+	}
+	r := newReconciler(source, replica, namespace("tenant"), namespace("victim"))
+	recorder := r.Recorder.(*record.FakeRecorder)
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(source)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(replica), &corev1.Secret{}); !errors.IsNotFound(err) {
+		t.Errorf("secret replicated from a namespace not allowed: %v", err)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "ReplicationRefused") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Error("no event for the refused secret")
+	}
+
+	// the namespaces of the allowlist are replicated from
+	r.SourceNamespaces = []string{"tenant"}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(replica), &corev1.Secret{}); err != nil {
+		t.Errorf("secret not replicated from an allowed namespace: %v", err)
+	}
+}
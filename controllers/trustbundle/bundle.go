@@ -0,0 +1,237 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package trustbundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"software.sslmate.com/src/go-pkcs12"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+// sourceError reports a missing or invalid source of a TrustBundle. The
+// bundle is not written while one of its sources is in error.
+type sourceError struct {
+	message string
+}
+
+func (e *sourceError) Error() string {
+	return e.message
+}
+
+func newSourceError(format string, args ...interface{}) error {
+	return &sourceError{message: fmt.Sprintf(format, args...)}
+}
+
+// bundleContent is the content of the ConfigMaps of a TrustBundle
+type bundleContent struct {
+	data       map[string]string
+	binaryData map[string][]byte
+	// hash covers the PEM bundle and the truststore settings, the truststores
+	// are only rebuilt when it changes
+	hash string
+}
+
+// assembleBundle reads the certificates of every source of the bundle, in
+// order, dropping duplicates and expired certificates
+func (r *TrustBundleReconciler) assembleBundle(ctx context.Context, bundle *operatorv1.TrustBundle) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	seen := map[string]bool{}
+	for i, src := range bundle.Spec.Sources {
+		data, err := r.readSource(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseCACertificates(data)
+		if err != nil {
+			return nil, newSourceError("source %d of TrustBundle %s: %v", i, bundle.Name, err)
+		}
+		for _, cert := range parsed {
+			if time.Now().After(cert.NotAfter) {
+				logd.Info("Skipping expired certificate", "TrustBundle", bundle.Name, "Subject", cert.Subject.String())
+				continue
+			}
+			if key := string(cert.Raw); !seen[key] {
+				seen[key] = true
+				certs = append(certs, cert)
+			}
+		}
+	}
+	if len(certs) == 0 {
+		return nil, newSourceError("TrustBundle %s has no valid CA certificate", bundle.Name)
+	}
+	return certs, nil
+}
+
+// readSource returns the PEM data of the source. Source secrets are labelled
+// to be watched, so that the bundle follows their rotation.
+func (r *TrustBundleReconciler) readSource(ctx context.Context, src operatorv1.TrustBundleSource) ([]byte, error) {
+	switch {
+	case src.Secret != nil:
+		secret := &corev1.Secret{}
+		if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: src.Secret.Namespace, Name: src.Secret.Name}, secret); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, newSourceError("secret %s/%s not found", src.Secret.Namespace, src.Secret.Name)
+			}
+			return nil, err
+		}
+		if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+			if secret.Labels == nil {
+				secret.Labels = map[string]string{}
+			}
+			secret.Labels[res.SecretWatchLabel] = ""
+			if err := r.Client.Update(ctx, secret); err != nil {
+				return nil, err
+			}
+		}
+		data, ok := secret.Data[src.Secret.Key]
+		if !ok {
+			return nil, newSourceError("key %s not found in secret %s/%s", src.Secret.Key, src.Secret.Namespace, src.Secret.Name)
+		}
+		return data, nil
+	case src.ConfigMap != nil:
+		cm := &corev1.ConfigMap{}
+		if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: src.ConfigMap.Namespace, Name: src.ConfigMap.Name}, cm); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, newSourceError("configmap %s/%s not found", src.ConfigMap.Namespace, src.ConfigMap.Name)
+			}
+			return nil, err
+		}
+		data, ok := cm.Data[src.ConfigMap.Key]
+		if !ok {
+			return nil, newSourceError("key %s not found in configmap %s/%s", src.ConfigMap.Key, src.ConfigMap.Namespace, src.ConfigMap.Name)
+		}
+		return []byte(data), nil
+	case src.InLine != "":
+		return []byte(src.InLine), nil
+	}
+	return nil, newSourceError("source has none of secret, configMap or inLine set")
+}
+
+// parseCACertificates parses PEM data that must only hold CA certificates
+func parseCACertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %s", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if !cert.IsCA {
+			return nil, fmt.Errorf("certificate %s is not a CA", cert.Subject.String())
+		}
+		certs = append(certs, cert)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		return nil, fmt.Errorf("invalid PEM data")
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return certs, nil
+}
+
+// buildBundle encodes the certificates as PEM and as the truststores asked
+// for by the bundle
+func buildBundle(bundle *operatorv1.TrustBundle, certs []*x509.Certificate) (*bundleContent, error) {
+	var pemBundle bytes.Buffer
+	for _, cert := range certs {
+		if err := pem.Encode(&pemBundle, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return nil, err
+		}
+	}
+
+	content := &bundleContent{data: map[string]string{bundle.Spec.Target.ConfigMap.Key: pemBundle.String()}}
+	h := sha256.New()
+	h.Write(pemBundle.Bytes())
+
+	formats := bundle.Spec.Target.AdditionalFormats
+	if formats == nil || (formats.JKS == nil && formats.PKCS12 == nil) {
+		content.hash = hex.EncodeToString(h.Sum(nil))
+		return content, nil
+	}
+	content.binaryData = map[string][]byte{}
+	if formats.JKS != nil {
+		password := trustStorePassword(formats.JKS)
+		jks, err := encodeJKS(certs, password)
+		if err != nil {
+			return nil, err
+		}
+		content.binaryData[formats.JKS.Key] = jks
+		fmt.Fprintf(h, "\njks:%s:%s", formats.JKS.Key, password)
+	}
+	if formats.PKCS12 != nil {
+		password := trustStorePassword(formats.PKCS12)
+		p12, err := pkcs12.EncodeTrustStore(rand.Reader, certs, password)
+		if err != nil {
+			return nil, err
+		}
+		content.binaryData[formats.PKCS12.Key] = p12
+		fmt.Fprintf(h, "\npkcs12:%s:%s", formats.PKCS12.Key, password)
+	}
+	content.hash = hex.EncodeToString(h.Sum(nil))
+	return content, nil
+}
+
+// encodeJKS encodes the certificates as a Java truststore, with each entry
+// named after the fingerprint of its certificate
+func encodeJKS(certs []*x509.Certificate, password string) ([]byte, error) {
+	ks := keystore.New()
+	for _, cert := range certs {
+		fingerprint := sha256.Sum256(cert.Raw)
+		err := ks.SetTrustedCertificateEntry(hex.EncodeToString(fingerprint[:]), keystore.TrustedCertificateEntry{
+			CreationTime: cert.NotBefore,
+			Certificate:  keystore.Certificate{Type: "X509", Content: cert.Raw},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, []byte(password)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func trustStorePassword(store *operatorv1.TrustStoreKey) string {
+	if store.Password == "" {
+		return res.DefaultTrustStorePassword
+	}
+	return store.Password
+}
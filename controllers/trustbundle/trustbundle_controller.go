@@ -0,0 +1,348 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package trustbundle
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+var logd = log.Log.WithName("controller_trustbundle")
+
+// SyncedCondition is the condition of a TrustBundle reporting whether its
+// ConfigMaps are up to date
+const SyncedCondition = "Synced"
+
+// resyncInterval is how often a TrustBundle is reconciled without any event,
+// to pick up changes to its ConfigMap sources, which are not watched, and to
+// repair edited copies of the bundle
+const resyncInterval = 10 * time.Minute
+
+// TrustBundleReconciler writes the CA certificates of each TrustBundle to a
+// ConfigMap in every namespace it selects
+type TrustBundleReconciler struct {
+	Client   client.Client
+	Reader   client.Reader
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=trustbundles,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operator.ibm.com,resources=trustbundles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
+
+// Reconcile assembles the bundle from its sources and writes it to the
+// selected namespaces, removing it from the namespaces no longer selected
+func (r *TrustBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := logd.WithValues("Request.Name", req.Name)
+
+	bundle := &operatorv1.TrustBundle{}
+	if err := r.Client.Get(ctx, req.NamespacedName, bundle); err != nil {
+		if errors.IsNotFound(err) {
+			// the copies are garbage collected with the TrustBundle
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	certs, err := r.assembleBundle(ctx, bundle)
+	if err != nil {
+		if _, ok := err.(*sourceError); !ok {
+			return ctrl.Result{}, err
+		}
+		reqLogger.Info("TrustBundle sources are not valid, keeping the current bundle", "reason", err.Error())
+		r.updateEvent(bundle, err.Error(), corev1.EventTypeWarning, "InvalidSource")
+		return ctrl.Result{RequeueAfter: resyncInterval}, r.updateStatus(ctx, bundle, operatorv1.TrustBundleStatus{
+			Certificates: bundle.Status.Certificates,
+			Namespaces:   bundle.Status.Namespaces,
+		}, metav1.ConditionFalse, "InvalidSource", err.Error())
+	}
+
+	content, err := buildBundle(bundle, certs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	namespaces, err := r.selectNamespaces(ctx, bundle)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	selected := map[string]bool{}
+	var conflicts []string
+	for _, ns := range namespaces {
+		selected[ns] = true
+		written, err := r.syncConfigMap(ctx, bundle, ns, content)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !written {
+			conflicts = append(conflicts, ns)
+		}
+	}
+	if err := r.removeStale(ctx, bundle, selected); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := operatorv1.TrustBundleStatus{
+		Certificates: len(certs),
+		Namespaces:   len(namespaces) - len(conflicts),
+	}
+	if len(conflicts) > 0 {
+		message := fmt.Sprintf("ConfigMap %s is not controlled by the TrustBundle in namespaces %s", bundle.Name, strings.Join(conflicts, ", "))
+		reqLogger.Info("TrustBundle not written over existing ConfigMaps", "namespaces", conflicts)
+		r.updateEvent(bundle, message, corev1.EventTypeWarning, "ConfigMapConflict")
+		return ctrl.Result{RequeueAfter: resyncInterval}, r.updateStatus(ctx, bundle, status, metav1.ConditionFalse, "ConfigMapConflict", message)
+	}
+
+	reqLogger.V(2).Info("TrustBundle synced", "certificates", len(certs), "namespaces", len(namespaces))
+	return ctrl.Result{RequeueAfter: resyncInterval}, r.updateStatus(ctx, bundle, status,
+		metav1.ConditionTrue, "Synced", fmt.Sprintf("Bundle of %d certificates written to %d namespaces", len(certs), len(namespaces)))
+}
+
+// selectNamespaces returns the active namespaces matching the namespace
+// selector of the bundle
+func (r *TrustBundleReconciler) selectNamespaces(ctx context.Context, bundle *operatorv1.TrustBundle) ([]string, error) {
+	selector := labels.Everything()
+	if bundle.Spec.Target.NamespaceSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(bundle.Spec.Target.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nsList := &corev1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, ns := range nsList.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating || ns.DeletionTimestamp != nil {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
+}
+
+// syncConfigMap writes the bundle to its ConfigMap in the namespace, unless
+// it is already up to date. A ConfigMap of the same name that the bundle does
+// not control is left alone, and false is returned.
+func (r *TrustBundleReconciler) syncConfigMap(ctx context.Context, bundle *operatorv1.TrustBundle, namespace string,
+	content *bundleContent) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.Reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: bundle.Name}, cm)
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        bundle.Name,
+				Namespace:   namespace,
+				Labels:      map[string]string{res.TrustBundleLabel: bundle.Name},
+				Annotations: map[string]string{res.TrustBundleHashAnnotation: content.hash},
+			},
+			Data:       content.data,
+			BinaryData: content.binaryData,
+		}
+		if err := controllerutil.SetControllerReference(bundle, cm, r.Scheme); err != nil {
+			return false, err
+		}
+		logd.Info("Creating trust bundle ConfigMap", "Namespace", namespace, "Name", bundle.Name)
+		return true, r.Client.Create(ctx, cm)
+	} else if err != nil {
+		return false, err
+	}
+
+	if !metav1.IsControlledBy(cm, bundle) {
+		return false, nil
+	}
+	if cm.Labels[res.TrustBundleLabel] == bundle.Name && cm.Annotations[res.TrustBundleHashAnnotation] == content.hash &&
+		reflect.DeepEqual(cm.Data, content.data) && sameKeys(cm.BinaryData, content.binaryData) {
+		return true, nil
+	}
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	cm.Labels[res.TrustBundleLabel] = bundle.Name
+	if cm.Annotations == nil {
+		cm.Annotations = map[string]string{}
+	}
+	cm.Annotations[res.TrustBundleHashAnnotation] = content.hash
+	cm.Data = content.data
+	cm.BinaryData = content.binaryData
+	logd.Info("Updating trust bundle ConfigMap", "Namespace", namespace, "Name", bundle.Name)
+	return true, r.Client.Update(ctx, cm)
+}
+
+// removeStale deletes the ConfigMaps of the bundle in the namespaces it no
+// longer selects
+func (r *TrustBundleReconciler) removeStale(ctx context.Context, bundle *operatorv1.TrustBundle, selected map[string]bool) error {
+	cmList := &corev1.ConfigMapList{}
+	if err := r.Reader.List(ctx, cmList, client.MatchingLabels{res.TrustBundleLabel: bundle.Name}); err != nil {
+		return err
+	}
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+		if selected[cm.Namespace] || cm.Name != bundle.Name || !metav1.IsControlledBy(cm, bundle) {
+			continue
+		}
+		logd.Info("Deleting trust bundle ConfigMap from unselected namespace", "Namespace", cm.Namespace, "Name", cm.Name)
+		if err := r.Client.Delete(ctx, cm); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TrustBundleReconciler) updateStatus(ctx context.Context, bundle *operatorv1.TrustBundle, status operatorv1.TrustBundleStatus,
+	conditionStatus metav1.ConditionStatus, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &operatorv1.TrustBundle{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(bundle), current); err != nil {
+			return err
+		}
+		status.Conditions = append([]metav1.Condition{}, current.Status.Conditions...)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               SyncedCondition,
+			Status:             conditionStatus,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: current.Generation,
+		})
+		if reflect.DeepEqual(current.Status, status) {
+			return nil
+		}
+		current.Status = status
+		return r.Client.Status().Update(ctx, current)
+	})
+}
+
+func (r *TrustBundleReconciler) updateEvent(instance runtime.Object, message, event, reason string) {
+	r.Recorder.Event(instance, event, reason, message)
+}
+
+func sameKeys(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TrustBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("trustbundle-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &operatorv1.TrustBundle{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{})
+	if err != nil {
+		return err
+	}
+
+	// Watch namespaces being created or relabelled, which may change the
+	// namespaces a bundle selects
+	err = c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.allBundles), predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch the labelled source secrets, to update the bundles on rotation
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.bundlesForSecret), predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+			newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+	})
+}
+
+func (r *TrustBundleReconciler) allBundles(obj client.Object) []reconcile.Request {
+	bundleList := &operatorv1.TrustBundleList{}
+	if err := r.Client.List(context.TODO(), bundleList); err != nil {
+		logd.Error(err, "Failed to list TrustBundles")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(bundleList.Items))
+	for _, bundle := range bundleList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+	}
+	return requests
+}
+
+func (r *TrustBundleReconciler) bundlesForSecret(obj client.Object) []reconcile.Request {
+	bundleList := &operatorv1.TrustBundleList{}
+	if err := r.Client.List(context.TODO(), bundleList); err != nil {
+		logd.Error(err, "Failed to list TrustBundles")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, bundle := range bundleList.Items {
+		for _, src := range bundle.Spec.Sources {
+			if src.Secret != nil && src.Secret.Namespace == obj.GetNamespace() && src.Secret.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,277 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package trustbundle
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func certificatePEM(t *testing.T, cn string, isCA bool) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newReconciler(t *testing.T, objs ...client.Object) *TrustBundleReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, operatorv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &TrustBundleReconciler{
+		Client:   c,
+		Reader:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+	}
+}
+
+func namespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestReconcileDistributesBundle(t *testing.T) {
+	csCA := certificatePEM(t, "cs-ca", true)
+	otherCA := certificatePEM(t, "other-ca", true)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: res.CSCASecretName, Namespace: res.DeployNamespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: csCA},
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "extra-cas", Namespace: res.DeployNamespace},
+		// the CS CA again, which is only added once
+		Data: map[string]string{"cas.pem": string(otherCA) + string(csCA)},
+	}
+	bundle := &operatorv1.TrustBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "cs-ca-bundle"},
+		Spec: operatorv1.TrustBundleSpec{
+			Sources: []operatorv1.TrustBundleSource{
+				{Secret: &operatorv1.SourceObjectKeySelector{Name: res.CSCASecretName, Namespace: res.DeployNamespace, Key: corev1.TLSCertKey}},
+				{ConfigMap: &operatorv1.SourceObjectKeySelector{Name: "extra-cas", Namespace: res.DeployNamespace, Key: "cas.pem"}},
+			},
+			Target: operatorv1.TrustBundleTarget{
+				ConfigMap:         operatorv1.TargetConfigMap{Key: res.TrustBundleKey},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"trust": "true"}},
+				AdditionalFormats: &operatorv1.TrustBundleFormats{
+					JKS:    &operatorv1.TrustStoreKey{Key: "truststore.jks"},
+					PKCS12: &operatorv1.TrustStoreKey{Key: "truststore.p12"},
+				},
+			},
+		},
+	}
+	r := newReconciler(t, secret, cm, bundle, namespace("app-a", map[string]string{"trust": "true"}), namespace("app-b", nil))
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: bundle.Name}, got); err != nil {
+		t.Fatal(err)
+	}
+	if want := string(csCA) + string(otherCA); got.Data[res.TrustBundleKey] != want {
+		t.Errorf("got bundle %q, want %q", got.Data[res.TrustBundleKey], want)
+	}
+	if len(got.BinaryData["truststore.jks"]) == 0 || len(got.BinaryData["truststore.p12"]) == 0 {
+		t.Errorf("truststores missing from %v", got.BinaryData)
+	}
+	jks := got.BinaryData["truststore.jks"]
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-b", Name: bundle.Name}, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+		t.Errorf("bundle written to unselected namespace: %v", err)
+	}
+
+	// the source secret is watched for rotation
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := secret.Labels[res.SecretWatchLabel]; !ok {
+		t.Errorf("source secret not labelled")
+	}
+
+	status := &operatorv1.TrustBundle{}
+	if err := r.Client.Get(ctx, req.NamespacedName, status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Status.Certificates != 2 || status.Status.Namespaces != 1 || !meta.IsStatusConditionTrue(status.Status.Conditions, SyncedCondition) {
+		t.Errorf("unexpected status %+v", status.Status)
+	}
+
+	// an unchanged bundle keeps its truststores
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	got = &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: bundle.Name}, got); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.BinaryData["truststore.jks"]) != string(jks) {
+		t.Errorf("truststore rewritten without a change to the bundle")
+	}
+
+	// rotating the CA and moving the label updates and moves the bundle
+	rotated := certificatePEM(t, "cs-ca-rotated", true)
+	secret.Data[corev1.TLSCertKey] = rotated
+	if err := r.Client.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	for name, labels := range map[string]map[string]string{"app-a": nil, "app-b": {"trust": "true"}} {
+		ns := &corev1.Namespace{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+			t.Fatal(err)
+		}
+		ns.Labels = labels
+		if err := r.Client.Update(ctx, ns); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	got = &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-b", Name: bundle.Name}, got); err != nil {
+		t.Fatal(err)
+	}
+	if want := string(rotated) + string(otherCA) + string(csCA); got.Data[res.TrustBundleKey] != want {
+		t.Errorf("got rotated bundle %q, want %q", got.Data[res.TrustBundleKey], want)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: bundle.Name}, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+		t.Errorf("bundle not removed from unselected namespace: %v", err)
+	}
+}
+
+func TestReconcileRejectsInvalidSource(t *testing.T) {
+	bundle := &operatorv1.TrustBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "leaf-bundle"},
+		Spec: operatorv1.TrustBundleSpec{
+			Sources: []operatorv1.TrustBundleSource{{InLine: string(certificatePEM(t, "leaf", false))}},
+			Target:  operatorv1.TrustBundleTarget{ConfigMap: operatorv1.TargetConfigMap{Key: res.TrustBundleKey}},
+		},
+	}
+	r := newReconciler(t, bundle, namespace("app-a", nil))
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-a", Name: bundle.Name}, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+		t.Errorf("invalid bundle written: %v", err)
+	}
+	status := &operatorv1.TrustBundle{}
+	if err := r.Client.Get(ctx, req.NamespacedName, status); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(status.Status.Conditions, SyncedCondition)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "InvalidSource" {
+		t.Errorf("unexpected Synced condition %+v", cond)
+	}
+}
+
+func TestReconcileSkipsForeignConfigMap(t *testing.T) {
+	bundle := &operatorv1.TrustBundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", UID: "bundle-uid"},
+		Spec: operatorv1.TrustBundleSpec{
+			Sources: []operatorv1.TrustBundleSource{{InLine: string(certificatePEM(t, "cs-ca", true))}},
+			Target:  operatorv1.TrustBundleTarget{ConfigMap: operatorv1.TargetConfigMap{Key: res.TrustBundleKey}},
+		},
+	}
+	// a ConfigMap of the same name, created by the application
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: bundle.Name, Namespace: "app-a"},
+		Data:       map[string]string{"ca.crt": "application CA"},
+	}
+	r := newReconciler(t, bundle, foreign, namespace("app-a", nil), namespace("app-b", nil))
+
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(foreign), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Data["ca.crt"] != "application CA" || got.Data[res.TrustBundleKey] != "" || len(got.OwnerReferences) != 0 {
+		t.Errorf("foreign ConfigMap overwritten: %+v", got)
+	}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: "app-b", Name: bundle.Name}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("bundle not written next to the conflict: %v", err)
+	}
+
+	status := &operatorv1.TrustBundle{}
+	if err := r.Client.Get(ctx, req.NamespacedName, status); err != nil {
+		t.Fatal(err)
+	}
+	cond := meta.FindStatusCondition(status.Status.Conditions, SyncedCondition)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "ConfigMapConflict" || status.Status.Namespaces != 1 {
+		t.Errorf("conflict not reported: %+v", status.Status)
+	}
+	select {
+	case event := <-r.Recorder.(*record.FakeRecorder).Events:
+		if !strings.Contains(event, "ConfigMapConflict") || !strings.Contains(event, "app-a") {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Errorf("no event for the conflict")
+	}
+}
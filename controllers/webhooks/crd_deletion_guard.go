@@ -0,0 +1,126 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+var log = logf.Log.WithName("webhook_crd_deletion_guard")
+
+// CRDDeletionGuardPath is the path the CRD deletion webhook is served on
+const CRDDeletionGuardPath = "/validate-crd-deletion"
+
+//+kubebuilder:webhook:path=/validate-crd-deletion,mutating=false,failurePolicy=fail,sideEffects=None,groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=delete,versions=v1,name=vcrddeletion.operator.ibm.com,admissionReviewVersions=v1
+
+// CRDDeletionGuard refuses the deletion of the cert-manager CRDs while any
+// Certificates, Issuers or ClusterIssuers still exist, unless the CRD has the
+// override annotation set
+type CRDDeletionGuard struct {
+	// Reader reads directly from the API server, so that resources are
+	// counted without starting informers on them
+	Reader  client.Reader
+	decoder *admission.Decoder
+}
+
+// Handle checks a CRD delete request against the remaining cert-manager resources
+func (g *CRDDeletionGuard) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete {
+		return admission.Allowed("")
+	}
+
+	crd := &apiextensionv1.CustomResourceDefinition{}
+	if err := g.decoder.DecodeRaw(req.OldObject, crd); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !isProtected(crd.Name) {
+		return admission.Allowed("")
+	}
+	if crd.Annotations[res.CRDDeletionOverrideAnno] == "true" {
+		log.Info("Deletion of protected CRD allowed by override annotation", "name", crd.Name)
+		return admission.Allowed("override annotation " + res.CRDDeletionOverrideAnno + " is set")
+	}
+
+	remaining, err := g.remainingResources(ctx)
+	if err != nil {
+		log.Error(err, "Error listing cert-manager resources")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(remaining) > 0 {
+		msg := fmt.Sprintf("CRD %s can not be deleted while %s still exist in the cluster. Delete them first, "+
+			"or set the annotation %s=true on the CRD to delete it anyway",
+			crd.Name, strings.Join(remaining, ", "), res.CRDDeletionOverrideAnno)
+		log.Info("Denied deletion of protected CRD", "name", crd.Name, "remaining", remaining)
+		return admission.Denied(msg)
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder into the CRDDeletionGuard
+func (g *CRDDeletionGuard) InjectDecoder(d *admission.Decoder) error {
+	g.decoder = d
+	return nil
+}
+
+// remainingResources returns the plural names of the guarded resources that
+// still have at least one object in the cluster
+func (g *CRDDeletionGuard) remainingResources(ctx context.Context) ([]string, error) {
+	var remaining []string
+	lists := []struct {
+		plural string
+		list   client.ObjectList
+	}{
+		{"certificates", &certmanagerv1.CertificateList{}},
+		{"issuers", &certmanagerv1.IssuerList{}},
+		{"clusterissuers", &certmanagerv1.ClusterIssuerList{}},
+	}
+	for _, l := range lists {
+		if err := g.Reader.List(ctx, l.list, client.Limit(1)); err != nil {
+			if meta.IsNoMatchError(err) {
+				// the CRD of this resource is already gone
+				continue
+			}
+			return nil, err
+		}
+		if meta.LenList(l.list) > 0 {
+			remaining = append(remaining, l.plural)
+		}
+	}
+	return remaining, nil
+}
+
+func isProtected(name string) bool {
+	for _, crd := range res.ProtectedCRDs {
+		if crd == name {
+			return true
+		}
+	}
+	return false
+}
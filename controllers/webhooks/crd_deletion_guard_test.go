@@ -0,0 +1,95 @@
+//
+// Copyright 2022 IBM Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
+	res "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+)
+
+func newGuard(t *testing.T, objs ...client.Object) *CRDDeletionGuard {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, apiextensionv1.AddToScheme, certmanagerv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &CRDDeletionGuard{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+	if err := g.InjectDecoder(decoder); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func deleteRequest(t *testing.T, name string, annotations map[string]string) admission.Request {
+	t.Helper()
+	crd := &apiextensionv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+	}
+	raw, err := json.Marshal(crd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Delete,
+		Name:      name,
+		OldObject: runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestCRDDeletionGuard(t *testing.T) {
+	issuer := &certmanagerv1.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "team"}}
+	protected := res.ProtectedCRDs[0]
+
+	for _, tc := range []struct {
+		name        string
+		guard       *CRDDeletionGuard
+		crd         string
+		annotations map[string]string
+		allowed     bool
+	}{
+		{"no resources left", newGuard(t), protected, nil, true},
+		{"resources left", newGuard(t, issuer), protected, nil, false},
+		{"override annotation", newGuard(t, issuer), protected, map[string]string{res.CRDDeletionOverrideAnno: "true"}, true},
+		{"override annotation not true", newGuard(t, issuer), protected, map[string]string{res.CRDDeletionOverrideAnno: "yes"}, false},
+		{"CRD not protected", newGuard(t, issuer), "others.example.com", nil, true},
+	} {
+		resp := tc.guard.Handle(context.TODO(), deleteRequest(t, tc.crd, tc.annotations))
+		if resp.Allowed != tc.allowed {
+			t.Errorf("%s: got allowed %v, want %v: %v", tc.name, resp.Allowed, tc.allowed, resp.Result)
+		}
+	}
+}
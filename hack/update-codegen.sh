@@ -0,0 +1,78 @@
+#!/usr/bin/env bash
+#
+# Copyright 2022 IBM Corporation
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# Generates the clientset, listers and informers in pkg/client for the
+# cert-manager.io, acme.cert-manager.io and operator.ibm.com API groups.
+#
+# client-gen names the group packages after the API directories, which aren't
+# valid Go identifiers here (cert-manager, acme.cert-manager), so the
+# generators run against temporary symlinks with Go friendly names and the
+# import paths are rewritten back to the real API packages afterwards.
+#
+# The packages are written to pkg/client, or to the directory given as the
+# fourth argument, which hack/verify-codegen.sh uses to compare them.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+USAGE="usage: $0 <client-gen> <lister-gen> <informer-gen> [output-dir]"
+CLIENT_GEN=${1:?${USAGE}}
+LISTER_GEN=${2:?${USAGE}}
+INFORMER_GEN=${3:?${USAGE}}
+
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+DEST=${4:-${ROOT}/pkg/client}
+MODULE=github.com/ibm/ibm-cert-manager-operator
+APIS=${MODULE}/apis
+OUTPUT=${MODULE}/pkg/client
+HEADER=${ROOT}/hack/boilerplate.go.txt
+
+TMP=$(mktemp -d)
+cleanup() {
+  rm -f "${ROOT}/apis/certmanager" "${ROOT}/apis/acme"
+  rm -rf "${TMP}"
+}
+trap cleanup EXIT
+
+ln -s cert-manager "${ROOT}/apis/certmanager"
+ln -s acme.cert-manager "${ROOT}/apis/acme"
+
+INPUT_DIRS=${APIS}/certmanager/v1,${APIS}/acme/v1,${APIS}/operator/v1
+
+cd "${ROOT}"
+"${CLIENT_GEN}" --clientset-name versioned \
+  --input-base "${APIS}" --input certmanager/v1,acme/v1,operator/v1 \
+  --output-package "${OUTPUT}/clientset" \
+  --go-header-file "${HEADER}" --output-base "${TMP}"
+"${LISTER_GEN}" --input-dirs "${INPUT_DIRS}" \
+  --output-package "${OUTPUT}/listers" \
+  --go-header-file "${HEADER}" --output-base "${TMP}"
+"${INFORMER_GEN}" --input-dirs "${INPUT_DIRS}" \
+  --versioned-clientset-package "${OUTPUT}/clientset/versioned" \
+  --listers-package "${OUTPUT}/listers" \
+  --output-package "${OUTPUT}/informers" \
+  --go-header-file "${HEADER}" --output-base "${TMP}"
+
+find "${TMP}" -name '*.go' -exec sed -i \
+  -e "s|\"${APIS}/certmanager/v1\"|\"${APIS}/cert-manager/v1\"|" \
+  -e "s|\"${APIS}/acme/v1\"|\"${APIS}/acme.cert-manager/v1\"|" {} +
+
+rm -rf "${DEST}"
+mkdir -p "$(dirname "${DEST}")"
+cp -r "${TMP}/${OUTPUT}" "${DEST}"
+gofmt -w "${DEST}"
@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+#
+# Copyright 2022 IBM Corporation
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# Fails when pkg/client is out of date with the API types. The clientset,
+# listers and informers are only published for consumers of the API packages,
+# nothing in the operator uses them, so this is what keeps them current.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+
+TMP=$(mktemp -d)
+trap 'rm -rf "${TMP}"' EXIT
+
+"${ROOT}/hack/update-codegen.sh" "$@" "${TMP}/client"
+
+if ! diff -Nru "${ROOT}/pkg/client" "${TMP}/client"; then
+  echo "pkg/client is out of date, run make generate-client" >&2
+  exit 1
+fi
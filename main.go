@@ -19,7 +19,10 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -41,6 +44,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	cache "github.com/IBM/controller-filtered-cache/filteredcache"
 	secretshare "github.com/IBM/ibm-secretshare-operator/api/v1"
@@ -50,8 +55,15 @@ import (
 	certmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/cert-manager/v1"
 	metacertmanagerv1 "github.com/ibm/ibm-cert-manager-operator/apis/meta.cert-manager/v1"
 	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/certificatemetrics"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/certificaterefresh"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/notification"
 	operatorcontrollers "github.com/ibm/ibm-cert-manager-operator/controllers/operator"
 	constants "github.com/ibm/ibm-cert-manager-operator/controllers/resources"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/revocation"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/secretreplicator"
+	"github.com/ibm/ibm-cert-manager-operator/controllers/trustbundle"
+	operatorwebhooks "github.com/ibm/ibm-cert-manager-operator/controllers/webhooks"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -76,8 +88,17 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var resyncPeriod time.Duration
+	var replicationNamespaces string
+	var clusterRevocationNamespaces string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.DurationVar(&resyncPeriod, "resync-period", operatorcontrollers.DefaultResyncPeriod,
+		"How often the CertManagerConfig is reconciled again, healing the objects created by the operator.")
+	flag.StringVar(&replicationNamespaces, "secret-replication-namespaces", "",
+		"The namespaces, separated by commas, secrets are replicated from besides the namespace of the operator.")
+	flag.StringVar(&clusterRevocationNamespaces, "cluster-revocation-namespaces", "",
+		"The namespaces, separated by commas, the CertificateRevocations of ClusterIssuers are honoured from besides the namespace of the operator.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -146,7 +167,7 @@ func main() {
 
 	kubeclient, _ := kubernetes.NewForConfig(mgr.GetConfig())
 	apiextclient, _ := apiextensionclientset.NewForConfig(mgr.GetConfig())
-	if err = (&operatorcontrollers.CertManagerReconciler{
+	certManagerReconciler := &operatorcontrollers.CertManagerReconciler{
 		Client:       mgr.GetClient(),
 		Reader:       mgr.GetAPIReader(),
 		Kubeclient:   kubeclient,
@@ -154,19 +175,125 @@ func main() {
 		Scheme:       mgr.GetScheme(),
 		Recorder:     mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
 		NS:           res.DeployNamespace,
-	}).SetupWithManager(mgr); err != nil {
+		ResyncPeriod: resyncPeriod,
+	}
+	if err = certManagerReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CertManager")
 		os.Exit(1)
 	}
+	if err = (&certificaterefresh.CertificateRefreshReconciler{
+		Client:   mgr.GetClient(),
+		Reader:   mgr.GetAPIReader(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CertificateRefresh")
+		os.Exit(1)
+	}
+	if err = (&certificaterefresh.RefreshPreviewReconciler{
+		Client: mgr.GetClient(),
+		Reader: mgr.GetAPIReader(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RefreshPreview")
+		os.Exit(1)
+	}
+	if err = (&trustbundle.TrustBundleReconciler{
+		Client:   mgr.GetClient(),
+		Reader:   mgr.GetAPIReader(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TrustBundle")
+		os.Exit(1)
+	}
+	if err = (&secretreplicator.SecretReplicatorReconciler{
+		Client:           mgr.GetClient(),
+		Reader:           mgr.GetAPIReader(),
+		Recorder:         mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+		NS:               res.DeployNamespace,
+		SourceNamespaces: namespaceList(replicationNamespaces),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SecretReplicator")
+		os.Exit(1)
+	}
+	crlStore := revocation.NewStore()
+	if err = (&revocation.CRLReconciler{
+		Client:                      mgr.GetClient(),
+		Reader:                      mgr.GetAPIReader(),
+		Scheme:                      mgr.GetScheme(),
+		Recorder:                    mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+		Store:                       crlStore,
+		NS:                          res.DeployNamespace,
+		ClusterRevocationNamespaces: namespaceList(clusterRevocationNamespaces),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CRL")
+		os.Exit(1)
+	}
+	ocspResponder := revocation.NewResponder()
+	if err = (&revocation.OCSPReconciler{
+		Client:                      mgr.GetClient(),
+		Reader:                      mgr.GetAPIReader(),
+		Scheme:                      mgr.GetScheme(),
+		Recorder:                    mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+		Responder:                   ocspResponder,
+		NS:                          res.DeployNamespace,
+		ClusterRevocationNamespaces: namespaceList(clusterRevocationNamespaces),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OCSP")
+		os.Exit(1)
+	}
+	revocationServer := &revocation.Server{
+		Addr:      fmt.Sprintf(":%d", constants.RevocationServerPort),
+		Store:     crlStore,
+		Responder: ocspResponder,
+	}
+	if err = mgr.Add(revocationServer); err != nil {
+		setupLog.Error(err, "unable to add revocation server")
+		os.Exit(1)
+	}
+	certificateCollector := certificatemetrics.NewCollector()
+	metrics.Registry.MustRegister(certificateCollector)
+	if err = (&certificatemetrics.CertificateMetricsReconciler{
+		Client:    mgr.GetClient(),
+		Collector: certificateCollector,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CertificateMetrics")
+		os.Exit(1)
+	}
+	if err = (&certificatemetrics.IssuerMetricsReconciler{
+		Client:    mgr.GetClient(),
+		Collector: certificateCollector,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IssuerMetrics")
+		os.Exit(1)
+	}
+	if err = (&notification.CertificateNotificationReconciler{
+		Client:   mgr.GetClient(),
+		Reader:   mgr.GetAPIReader(),
+		Recorder: mgr.GetEventRecorderFor("ibm-cert-manager-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CertificateNotification")
+		os.Exit(1)
+	}
+	// the CRD deletion guard needs a serving certificate and a caBundle,
+	// which are only provisioned with the [WEBHOOK] sections of config/default
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		mgr.GetWebhookServer().Register(operatorwebhooks.CRDDeletionGuardPath, &webhook.Admission{
+			Handler: &operatorwebhooks.CRDDeletionGuard{Reader: mgr.GetAPIReader()},
+		})
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
-		os.Exit(1)
+	for name, check := range certManagerReconciler.ReadyzChecks(mgr.GetCache(), mgr.Elected()) {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			os.Exit(1)
+		}
 	}
 
 	setupLog.Info("starting manager")
@@ -175,3 +302,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// namespaceList splits a list of namespaces separated by commas
+func namespaceList(list string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(list, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
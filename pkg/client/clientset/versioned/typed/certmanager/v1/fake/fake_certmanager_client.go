@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/ibm/ibm-cert-manager-operator/pkg/client/clientset/versioned/typed/certmanager/v1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeCertmanagerV1 struct {
+	*testing.Fake
+}
+
+func (c *FakeCertmanagerV1) Certificates(namespace string) v1.CertificateInterface {
+	return &FakeCertificates{c, namespace}
+}
+
+func (c *FakeCertmanagerV1) CertificateRequests(namespace string) v1.CertificateRequestInterface {
+	return &FakeCertificateRequests{c, namespace}
+}
+
+func (c *FakeCertmanagerV1) ClusterIssuers() v1.ClusterIssuerInterface {
+	return &FakeClusterIssuers{c}
+}
+
+func (c *FakeCertmanagerV1) Issuers(namespace string) v1.IssuerInterface {
+	return &FakeIssuers{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeCertmanagerV1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	scheme "github.com/ibm/ibm-cert-manager-operator/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CertificateNotificationPoliciesGetter has a method to return a CertificateNotificationPolicyInterface.
+// A group's client should implement this interface.
+type CertificateNotificationPoliciesGetter interface {
+	CertificateNotificationPolicies() CertificateNotificationPolicyInterface
+}
+
+// CertificateNotificationPolicyInterface has methods to work with CertificateNotificationPolicy resources.
+type CertificateNotificationPolicyInterface interface {
+	Create(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.CreateOptions) (*v1.CertificateNotificationPolicy, error)
+	Update(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.UpdateOptions) (*v1.CertificateNotificationPolicy, error)
+	UpdateStatus(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.UpdateOptions) (*v1.CertificateNotificationPolicy, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CertificateNotificationPolicy, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CertificateNotificationPolicyList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CertificateNotificationPolicy, err error)
+	CertificateNotificationPolicyExpansion
+}
+
+// certificateNotificationPolicies implements CertificateNotificationPolicyInterface
+type certificateNotificationPolicies struct {
+	client rest.Interface
+}
+
+// newCertificateNotificationPolicies returns a CertificateNotificationPolicies
+func newCertificateNotificationPolicies(c *OperatorV1Client) *certificateNotificationPolicies {
+	return &certificateNotificationPolicies{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the certificateNotificationPolicy, and returns the corresponding certificateNotificationPolicy object, and an error if there is any.
+func (c *certificateNotificationPolicies) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CertificateNotificationPolicy, err error) {
+	result = &v1.CertificateNotificationPolicy{}
+	err = c.client.Get().
+		Resource("certificatenotificationpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CertificateNotificationPolicies that match those selectors.
+func (c *certificateNotificationPolicies) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CertificateNotificationPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CertificateNotificationPolicyList{}
+	err = c.client.Get().
+		Resource("certificatenotificationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested certificateNotificationPolicies.
+func (c *certificateNotificationPolicies) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("certificatenotificationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a certificateNotificationPolicy and creates it.  Returns the server's representation of the certificateNotificationPolicy, and an error, if there is any.
+func (c *certificateNotificationPolicies) Create(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.CreateOptions) (result *v1.CertificateNotificationPolicy, err error) {
+	result = &v1.CertificateNotificationPolicy{}
+	err = c.client.Post().
+		Resource("certificatenotificationpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(certificateNotificationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a certificateNotificationPolicy and updates it. Returns the server's representation of the certificateNotificationPolicy, and an error, if there is any.
+func (c *certificateNotificationPolicies) Update(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.UpdateOptions) (result *v1.CertificateNotificationPolicy, err error) {
+	result = &v1.CertificateNotificationPolicy{}
+	err = c.client.Put().
+		Resource("certificatenotificationpolicies").
+		Name(certificateNotificationPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(certificateNotificationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *certificateNotificationPolicies) UpdateStatus(ctx context.Context, certificateNotificationPolicy *v1.CertificateNotificationPolicy, opts metav1.UpdateOptions) (result *v1.CertificateNotificationPolicy, err error) {
+	result = &v1.CertificateNotificationPolicy{}
+	err = c.client.Put().
+		Resource("certificatenotificationpolicies").
+		Name(certificateNotificationPolicy.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(certificateNotificationPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the certificateNotificationPolicy and deletes it. Returns an error if one occurs.
+func (c *certificateNotificationPolicies) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("certificatenotificationpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *certificateNotificationPolicies) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("certificatenotificationpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched certificateNotificationPolicy.
+func (c *certificateNotificationPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CertificateNotificationPolicy, err error) {
+	result = &v1.CertificateNotificationPolicy{}
+	err = c.client.Patch(pt).
+		Resource("certificatenotificationpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCertManagerConfigs implements CertManagerConfigInterface
+type FakeCertManagerConfigs struct {
+	Fake *FakeOperatorV1
+}
+
+var certmanagerconfigsResource = schema.GroupVersionResource{Group: "operator.ibm.com", Version: "v1", Resource: "certmanagerconfigs"}
+
+var certmanagerconfigsKind = schema.GroupVersionKind{Group: "operator.ibm.com", Version: "v1", Kind: "CertManagerConfig"}
+
+// Get takes name of the certManagerConfig, and returns the corresponding certManagerConfig object, and an error if there is any.
+func (c *FakeCertManagerConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *operatorv1.CertManagerConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(certmanagerconfigsResource, name), &operatorv1.CertManagerConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorv1.CertManagerConfig), err
+}
+
+// List takes label and field selectors, and returns the list of CertManagerConfigs that match those selectors.
+func (c *FakeCertManagerConfigs) List(ctx context.Context, opts v1.ListOptions) (result *operatorv1.CertManagerConfigList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(certmanagerconfigsResource, certmanagerconfigsKind, opts), &operatorv1.CertManagerConfigList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &operatorv1.CertManagerConfigList{ListMeta: obj.(*operatorv1.CertManagerConfigList).ListMeta}
+	for _, item := range obj.(*operatorv1.CertManagerConfigList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested certManagerConfigs.
+func (c *FakeCertManagerConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(certmanagerconfigsResource, opts))
+}
+
+// Create takes the representation of a certManagerConfig and creates it.  Returns the server's representation of the certManagerConfig, and an error, if there is any.
+func (c *FakeCertManagerConfigs) Create(ctx context.Context, certManagerConfig *operatorv1.CertManagerConfig, opts v1.CreateOptions) (result *operatorv1.CertManagerConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(certmanagerconfigsResource, certManagerConfig), &operatorv1.CertManagerConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorv1.CertManagerConfig), err
+}
+
+// Update takes the representation of a certManagerConfig and updates it. Returns the server's representation of the certManagerConfig, and an error, if there is any.
+func (c *FakeCertManagerConfigs) Update(ctx context.Context, certManagerConfig *operatorv1.CertManagerConfig, opts v1.UpdateOptions) (result *operatorv1.CertManagerConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(certmanagerconfigsResource, certManagerConfig), &operatorv1.CertManagerConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorv1.CertManagerConfig), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCertManagerConfigs) UpdateStatus(ctx context.Context, certManagerConfig *operatorv1.CertManagerConfig, opts v1.UpdateOptions) (*operatorv1.CertManagerConfig, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(certmanagerconfigsResource, "status", certManagerConfig), &operatorv1.CertManagerConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorv1.CertManagerConfig), err
+}
+
+// Delete takes name of the certManagerConfig and deletes it. Returns an error if one occurs.
+func (c *FakeCertManagerConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(certmanagerconfigsResource, name), &operatorv1.CertManagerConfig{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCertManagerConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(certmanagerconfigsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &operatorv1.CertManagerConfigList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched certManagerConfig.
+func (c *FakeCertManagerConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *operatorv1.CertManagerConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(certmanagerconfigsResource, name, pt, data, subresources...), &operatorv1.CertManagerConfig{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*operatorv1.CertManagerConfig), err
+}
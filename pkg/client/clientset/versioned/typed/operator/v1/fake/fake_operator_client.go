@@ -0,0 +1,51 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "github.com/ibm/ibm-cert-manager-operator/pkg/client/clientset/versioned/typed/operator/v1"
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+)
+
+type FakeOperatorV1 struct {
+	*testing.Fake
+}
+
+func (c *FakeOperatorV1) CertManagerConfigs() v1.CertManagerConfigInterface {
+	return &FakeCertManagerConfigs{c}
+}
+
+func (c *FakeOperatorV1) CertificateNotificationPolicies() v1.CertificateNotificationPolicyInterface {
+	return &FakeCertificateNotificationPolicies{c}
+}
+
+func (c *FakeOperatorV1) CertificateRevocations(namespace string) v1.CertificateRevocationInterface {
+	return &FakeCertificateRevocations{c, namespace}
+}
+
+func (c *FakeOperatorV1) TrustBundles() v1.TrustBundleInterface {
+	return &FakeTrustBundles{c}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeOperatorV1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
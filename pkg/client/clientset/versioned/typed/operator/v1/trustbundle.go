@@ -0,0 +1,183 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	scheme "github.com/ibm/ibm-cert-manager-operator/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// TrustBundlesGetter has a method to return a TrustBundleInterface.
+// A group's client should implement this interface.
+type TrustBundlesGetter interface {
+	TrustBundles() TrustBundleInterface
+}
+
+// TrustBundleInterface has methods to work with TrustBundle resources.
+type TrustBundleInterface interface {
+	Create(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.CreateOptions) (*v1.TrustBundle, error)
+	Update(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.UpdateOptions) (*v1.TrustBundle, error)
+	UpdateStatus(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.UpdateOptions) (*v1.TrustBundle, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TrustBundle, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.TrustBundleList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TrustBundle, err error)
+	TrustBundleExpansion
+}
+
+// trustBundles implements TrustBundleInterface
+type trustBundles struct {
+	client rest.Interface
+}
+
+// newTrustBundles returns a TrustBundles
+func newTrustBundles(c *OperatorV1Client) *trustBundles {
+	return &trustBundles{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the trustBundle, and returns the corresponding trustBundle object, and an error if there is any.
+func (c *trustBundles) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.TrustBundle, err error) {
+	result = &v1.TrustBundle{}
+	err = c.client.Get().
+		Resource("trustbundles").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of TrustBundles that match those selectors.
+func (c *trustBundles) List(ctx context.Context, opts metav1.ListOptions) (result *v1.TrustBundleList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.TrustBundleList{}
+	err = c.client.Get().
+		Resource("trustbundles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested trustBundles.
+func (c *trustBundles) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("trustbundles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a trustBundle and creates it.  Returns the server's representation of the trustBundle, and an error, if there is any.
+func (c *trustBundles) Create(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.CreateOptions) (result *v1.TrustBundle, err error) {
+	result = &v1.TrustBundle{}
+	err = c.client.Post().
+		Resource("trustbundles").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(trustBundle).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a trustBundle and updates it. Returns the server's representation of the trustBundle, and an error, if there is any.
+func (c *trustBundles) Update(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.UpdateOptions) (result *v1.TrustBundle, err error) {
+	result = &v1.TrustBundle{}
+	err = c.client.Put().
+		Resource("trustbundles").
+		Name(trustBundle.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(trustBundle).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *trustBundles) UpdateStatus(ctx context.Context, trustBundle *v1.TrustBundle, opts metav1.UpdateOptions) (result *v1.TrustBundle, err error) {
+	result = &v1.TrustBundle{}
+	err = c.client.Put().
+		Resource("trustbundles").
+		Name(trustBundle.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(trustBundle).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the trustBundle and deletes it. Returns an error if one occurs.
+func (c *trustBundles) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("trustbundles").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *trustBundles) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("trustbundles").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched trustBundle.
+func (c *trustBundles) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TrustBundle, err error) {
+	result = &v1.TrustBundle{}
+	err = c.client.Patch(pt).
+		Resource("trustbundles").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
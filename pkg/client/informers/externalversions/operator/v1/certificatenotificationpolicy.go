@@ -0,0 +1,88 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	time "time"
+
+	operatorv1 "github.com/ibm/ibm-cert-manager-operator/apis/operator/v1"
+	versioned "github.com/ibm/ibm-cert-manager-operator/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/ibm/ibm-cert-manager-operator/pkg/client/informers/externalversions/internalinterfaces"
+	v1 "github.com/ibm/ibm-cert-manager-operator/pkg/client/listers/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// CertificateNotificationPolicyInformer provides access to a shared informer and lister for
+// CertificateNotificationPolicies.
+type CertificateNotificationPolicyInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.CertificateNotificationPolicyLister
+}
+
+type certificateNotificationPolicyInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewCertificateNotificationPolicyInformer constructs a new informer for CertificateNotificationPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewCertificateNotificationPolicyInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredCertificateNotificationPolicyInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredCertificateNotificationPolicyInformer constructs a new informer for CertificateNotificationPolicy type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredCertificateNotificationPolicyInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.OperatorV1().CertificateNotificationPolicies().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.OperatorV1().CertificateNotificationPolicies().Watch(context.TODO(), options)
+			},
+		},
+		&operatorv1.CertificateNotificationPolicy{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *certificateNotificationPolicyInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredCertificateNotificationPolicyInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *certificateNotificationPolicyInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&operatorv1.CertificateNotificationPolicy{}, f.defaultInformer)
+}
+
+func (f *certificateNotificationPolicyInformer) Lister() v1.CertificateNotificationPolicyLister {
+	return v1.NewCertificateNotificationPolicyLister(f.Informer().GetIndexer())
+}
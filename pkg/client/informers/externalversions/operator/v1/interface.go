@@ -0,0 +1,65 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	internalinterfaces "github.com/ibm/ibm-cert-manager-operator/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// CertManagerConfigs returns a CertManagerConfigInformer.
+	CertManagerConfigs() CertManagerConfigInformer
+	// CertificateNotificationPolicies returns a CertificateNotificationPolicyInformer.
+	CertificateNotificationPolicies() CertificateNotificationPolicyInformer
+	// CertificateRevocations returns a CertificateRevocationInformer.
+	CertificateRevocations() CertificateRevocationInformer
+	// TrustBundles returns a TrustBundleInformer.
+	TrustBundles() TrustBundleInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// CertManagerConfigs returns a CertManagerConfigInformer.
+func (v *version) CertManagerConfigs() CertManagerConfigInformer {
+	return &certManagerConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// CertificateNotificationPolicies returns a CertificateNotificationPolicyInformer.
+func (v *version) CertificateNotificationPolicies() CertificateNotificationPolicyInformer {
+	return &certificateNotificationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// CertificateRevocations returns a CertificateRevocationInformer.
+func (v *version) CertificateRevocations() CertificateRevocationInformer {
+	return &certificateRevocationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TrustBundles returns a TrustBundleInformer.
+func (v *version) TrustBundles() TrustBundleInformer {
+	return &trustBundleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}